@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FirmwareAdvisories is a database of drive firmware known to be broken,
+// loaded from a JSON file:
+//
+//	{
+//	  "advisories": [
+//	    {
+//	      "id": "a00092491en_us",
+//	      "severity": "critical",
+//	      "model": "*VO0480JFDGT",
+//	      "firmware_max": "HPD7"
+//	    }
+//	  ]
+//	}
+//
+// Models are glob patterns (see path.Match) matched against the drive model
+// as printed by ssacli with runs of whitespace collapsed. A drive is
+// affected when its model matches and its firmware lies within
+// firmware_min and firmware_max, both inclusive and optional.
+type FirmwareAdvisories struct {
+	Advisories []FirmwareAdvisory `json:"advisories"`
+}
+
+// FirmwareAdvisory is a single vendor advisory.
+type FirmwareAdvisory struct {
+	ID          string `json:"id"`
+	Severity    string `json:"severity"`
+	Model       string `json:"model"`
+	FirmwareMin string `json:"firmware_min"`
+	FirmwareMax string `json:"firmware_max"`
+}
+
+// LoadFirmwareAdvisories reads and validates an advisory database.
+func LoadFirmwareAdvisories(file string) (*FirmwareAdvisories, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var a FirmwareAdvisories
+	if err := json.Unmarshal(raw, &a); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	for _, adv := range a.Advisories {
+		if adv.ID == "" || adv.Model == "" {
+			return nil, fmt.Errorf("advisory without id or model in %s", file)
+		}
+		if _, err := path.Match(adv.Model, ""); err != nil {
+			return nil, fmt.Errorf("invalid model pattern %q in advisory %s: %w", adv.Model, adv.ID, err)
+		}
+	}
+
+	return &a, nil
+}
+
+// affecting returns the advisories that apply to a drive.
+func (a *FirmwareAdvisories) affecting(model string, firmware string) []FirmwareAdvisory {
+	if a == nil || firmware == "" {
+		return nil
+	}
+
+	model = strings.Join(strings.Fields(model), " ")
+
+	var hits []FirmwareAdvisory
+	for _, adv := range a.Advisories {
+		if ok, _ := path.Match(adv.Model, model); !ok {
+			continue
+		}
+		if adv.FirmwareMin != "" && compareFirmware(firmware, adv.FirmwareMin) < 0 {
+			continue
+		}
+		if adv.FirmwareMax != "" && compareFirmware(firmware, adv.FirmwareMax) > 0 {
+			continue
+		}
+		hits = append(hits, adv)
+	}
+	return hits
+}
+
+// compareFirmware orders firmware revisions such as "HPD7" and "HPD10" by
+// comparing runs of digits numerically and everything else as text.
+func compareFirmware(a string, b string) int {
+	ra, rb := firmwareRuns(a), firmwareRuns(b)
+	for i := 0; i < len(ra) && i < len(rb); i++ {
+		na, errA := strconv.Atoi(ra[i])
+		nb, errB := strconv.Atoi(rb[i])
+
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return cmp.Compare(na, nb)
+			}
+		case ra[i] != rb[i]:
+			return strings.Compare(ra[i], rb[i])
+		}
+	}
+	return cmp.Compare(len(ra), len(rb))
+}
+
+func firmwareRuns(s string) []string {
+	var runs []string
+	start := 0
+	for i := 1; i <= len(s); i++ {
+		if i == len(s) || unicode.IsDigit(rune(s[i])) != unicode.IsDigit(rune(s[i-1])) {
+			runs = append(runs, s[start:i])
+			start = i
+		}
+	}
+	return runs
+}
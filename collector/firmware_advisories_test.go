@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"testing"
+)
+
+func TestCompareFirmware(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		// Equal revisions, also when digits are zero padded
+		{"HPD7", "HPD7", 0},
+		{"HPD07", "HPD7", 0},
+		// Digit runs compare numerically, not as text
+		{"HPD10", "HPD9", 1},
+		{"HPD9", "HPD10", -1},
+		{"4.10", "4.9", 1},
+		// Letter runs compare as text
+		{"HPD8", "HPE1", -1},
+		{"HPDA", "HPD7", 1},
+		// A revision that extends another sorts after it
+		{"HPD7", "HPD7A", -1},
+		{"HPD7A", "HPD7", 1},
+		{"", "HPD1", -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareFirmware(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareFirmware(%q, %q): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestFirmwareRuns(t *testing.T) {
+	got := firmwareRuns("HPD10a2")
+	want := []string{"HPD", "10", "a", "2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+	if runs := firmwareRuns(""); runs != nil {
+		t.Errorf("expected no runs for an empty revision, got %q", runs)
+	}
+}
+
+func TestFirmwareAdvisoriesAffecting(t *testing.T) {
+	advisories, err := LoadFirmwareAdvisories(writeTestFile(t, `{
+  "advisories": [
+    {"id": "upto", "severity": "critical", "model": "*VO0480JFDGT", "firmware_max": "HPD7"},
+    {"id": "range", "severity": "warning", "model": "HP EG0*", "firmware_min": "HPD2", "firmware_max": "HPD4"},
+    {"id": "from", "severity": "info", "model": "HP EG0600FBVFP", "firmware_min": "HPD9"}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("LoadFirmwareAdvisories: %v", err)
+	}
+
+	tests := []struct {
+		model    string
+		firmware string
+		want     []string
+	}{
+		// firmware_max is inclusive, later revisions are fixed
+		{"HP VO0480JFDGT", "HPD6", []string{"upto"}},
+		{"HP VO0480JFDGT", "HPD7", []string{"upto"}},
+		{"HP VO0480JFDGT", "HPD8", nil},
+		{"HP VO0480JFDGT", "HPD10", nil},
+		// Both bounds are inclusive
+		{"HP EG0300FCVBF", "HPD1", nil},
+		{"HP EG0300FCVBF", "HPD2", []string{"range"}},
+		{"HP EG0300FCVBF", "HPD4", []string{"range"}},
+		{"HP EG0300FCVBF", "HPD5", nil},
+		// Several advisories can match one drive, whitespace in the
+		// model is collapsed before matching
+		{"HP   EG0600FBVFP", "HPD3", []string{"range"}},
+		{"HP EG0600FBVFP", "HPD10", []string{"from"}},
+		// The glob must match the whole model
+		{"HP EG0600FBVFP-X", "HPD10", nil},
+		{"HP MO0400JDVEU", "HPD1", nil},
+		// Drives without a firmware revision are never matched
+		{"HP VO0480JFDGT", "", nil},
+	}
+
+	for _, tt := range tests {
+		hits := advisories.affecting(tt.model, tt.firmware)
+		var got []string
+		for _, h := range hits {
+			got = append(got, h.ID)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("affecting(%q, %q): expected %v, got %v", tt.model, tt.firmware, tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("affecting(%q, %q): expected %v, got %v", tt.model, tt.firmware, tt.want, got)
+			}
+		}
+	}
+
+	var none *FirmwareAdvisories
+	if hits := none.affecting("HP VO0480JFDGT", "HPD6"); hits != nil {
+		t.Errorf("expected no advisories when none are loaded, got %v", hits)
+	}
+}
+
+func TestLoadFirmwareAdvisoriesInvalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed JSON":  `{"advisories": [`,
+		"missing id":      `{"advisories": [{"model": "*"}]}`,
+		"missing model":   `{"advisories": [{"id": "a1"}]}`,
+		"invalid pattern": `{"advisories": [{"id": "a1", "model": "[HP"}]}`,
+	}
+
+	for name, content := range tests {
+		if _, err := LoadFirmwareAdvisories(writeTestFile(t, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package collector
+
+import (
+	"regexp"
+)
+
+// controllerLinkRates is the highest SAS link rate in Gbps each Smart Array
+// controller family supports. ssacli does not report it, so it is keyed by
+// the family in the model name, e.g. "P440" for "Smart Array P440ar".
+var controllerLinkRates = map[string]float64{
+	// 3Gb/s SAS
+	"E200": 3, "E500": 3, "P400": 3, "P700": 3, "P800": 3,
+	// 6Gb/s SAS
+	"B320": 6, "H220": 6, "H221": 6, "H222": 6, "P212": 6, "P220": 6,
+	"P222": 6, "P410": 6, "P411": 6, "P420": 6, "P421": 6, "P711": 6,
+	"P712": 6, "P721": 6, "P812": 6, "P822": 6,
+	// 12Gb/s SAS
+	"E208": 12, "H240": 12, "H241": 12, "H244": 12, "P204": 12, "P240": 12,
+	"P244": 12, "P246": 12, "P408": 12, "P430": 12, "P431": 12, "P440": 12,
+	"P441": 12, "P542": 12, "P731": 12, "P741": 12, "P816": 12, "P830": 12,
+	"P840": 12,
+}
+
+var controllerFamilyRe = regexp.MustCompile(`\b([BEHP]\d{3})`)
+
+// ControllerLinkRate returns the highest link rate in Gbps a controller
+// model supports, 0 for models not in the table.
+func ControllerLinkRate(model string) float64 {
+	m := controllerFamilyRe.FindStringSubmatch(model)
+	if m == nil {
+		return 0
+	}
+	return controllerLinkRates[m[1]]
+}
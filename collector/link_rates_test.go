@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+)
+
+func TestControllerLinkRate(t *testing.T) {
+	tests := map[string]float64{
+		"Smart Array P440ar":               12,
+		"HPE Smart Array P408i-a SR Gen10": 12,
+		"Smart Array P420i":                6,
+		"Smart Array P400":                 3,
+		"Dynamic Smart Array B140i":        0,
+		"":                                 0,
+	}
+	for model, want := range tests {
+		if got := ControllerLinkRate(model); got != want {
+			t.Errorf("ControllerLinkRate(%q): expected %v, got %v", model, want, got)
+		}
+	}
+}
+
+func TestLinkDegraded(t *testing.T) {
+	tests := []struct {
+		name       string
+		negotiated []float64
+		maximum    []float64
+		controller float64
+		degraded   bool
+		known      bool
+	}{
+		// Every drive behind a bad backplane dropping to 6G is still
+		// degraded, whatever the other drives negotiated
+		{"12G drive at 6G on 12G controller", []float64{6, 0}, []float64{12, 12}, 12, true, true},
+		{"12G drive at 12G", []float64{12, 0}, []float64{12, 12}, 12, false, true},
+		{"12G drive on 6G controller", []float64{6}, []float64{12}, 6, false, true},
+		{"SATA drive at its maximum", []float64{6}, []float64{6}, 12, false, true},
+		{"unknown controller, drive below its maximum", []float64{3}, []float64{12}, 0, true, true},
+		{"no link", []float64{0}, []float64{12}, 12, false, false},
+		{"no maximum reported", []float64{6}, nil, 12, false, false},
+	}
+
+	for _, tt := range tests {
+		disk := parser.SsacliPhysDiskData{PHYTransferRates: tt.negotiated, PHYMaximumLinkRates: tt.maximum}
+		degraded, known := linkDegraded(disk, tt.controller)
+		if degraded != tt.degraded || known != tt.known {
+			t.Errorf("%s: expected (%v, %v), got (%v, %v)", tt.name, tt.degraded, tt.known, degraded, known)
+		}
+	}
+}
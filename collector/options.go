@@ -0,0 +1,35 @@
+package collector
+
+import "time"
+
+// Options holds the exporter-wide settings shared by the ssacli collectors.
+type Options struct {
+	// Legacy keeps the pre-v2 shape of the status metrics, which carry
+	// volatile values such as status strings and temperatures as labels.
+	Legacy bool
+
+	// TemperatureThresholds are exported next to the physical drive
+	// temperatures, nil disables the threshold metric.
+	TemperatureThresholds *TemperatureThresholds
+
+	// FirmwareAdvisories are evaluated against every physical drive, nil
+	// disables the advisory metric.
+	FirmwareAdvisories *FirmwareAdvisories
+
+	// ReallocatedSectorsLimit is the number of reallocated sectors (grown
+	// defects on SAS drives) above which a drive is recommended for
+	// replacement, 0 disables the check.
+	ReallocatedSectorsLimit float64
+
+	// LogEvents writes controller events that appeared since the previous
+	// scrape to the exporter log.
+	LogEvents bool
+
+	// DiagFile is the Array Diagnostic Utility report read for drive error
+	// counters, empty disables the diag collector.
+	DiagFile string
+
+	// DiagInterval regenerates DiagFile with "ssacli ctrl all diag" once it
+	// is older than this, 0 only reads an existing report.
+	DiagInterval time.Duration
+}
@@ -0,0 +1,52 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// progressTracker remembers when a long running operation was first seen
+// and how far it had got at that point. Collectors are created for every
+// scrape, so the observations have to outlive them.
+type progressTracker struct {
+	mu   sync.Mutex
+	seen map[string]progressSample
+}
+
+type progressSample struct {
+	at    time.Time
+	ratio float64
+}
+
+var progress = &progressTracker{seen: make(map[string]progressSample)}
+
+// eta estimates the seconds left until the operation identified by key
+// reaches a ratio of 1, based on the average rate since it was first seen.
+// It returns false until the operation has made observable progress.
+func (t *progressTracker) eta(key string, ratio float64, now time.Time) (float64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	first, ok := t.seen[key]
+	if !ok || ratio < first.ratio {
+		// First sighting, or the operation was restarted
+		t.seen[key] = progressSample{at: now, ratio: ratio}
+		return 0, false
+	}
+
+	done := ratio - first.ratio
+	elapsed := now.Sub(first.at).Seconds()
+	if done <= 0 || elapsed <= 0 {
+		return 0, false
+	}
+
+	return (1 - ratio) * elapsed / done, true
+}
+
+// forget drops the observations of an operation that is no longer running.
+func (t *progressTracker) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.seen, key)
+}
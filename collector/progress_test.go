@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestProgressTrackerETA(t *testing.T) {
+	tr := &progressTracker{seen: make(map[string]progressSample)}
+	start := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		after time.Duration
+		ratio float64
+		eta   float64
+		ok    bool
+	}{
+		// First sighting only records the starting point
+		{0, 0.2, 0, false},
+		// No progress yet
+		{time.Minute, 0.2, 0, false},
+		// 0.2 done in 10 minutes, 0.6 left
+		{10 * time.Minute, 0.4, 1800, true},
+		// Restarted, starts over
+		{20 * time.Minute, 0.1, 0, false},
+		{30 * time.Minute, 0.4, 1200, true},
+	} {
+		eta, ok := tr.eta("0/1/rebuild", tc.ratio, start.Add(tc.after))
+		if ok != tc.ok || math.Abs(eta-tc.eta) > 1e-6 {
+			t.Errorf("after %v at %v: expected %v %v, got %v %v", tc.after, tc.ratio, tc.eta, tc.ok, eta, ok)
+		}
+	}
+
+	tr.forget("0/1/rebuild")
+	if _, ok := tr.eta("0/1/rebuild", 0.6, start.Add(time.Hour)); ok {
+		t.Error("expected a forgotten operation to start over")
+	}
+}
+
+func TestLogDiskRebuildProgressSeries(t *testing.T) {
+	for _, tc := range []struct {
+		status string
+		want   []float64
+	}{
+		{"Recovering, 43% complete", []float64{0.43}},
+		{"OK", nil},
+		{"Recovering, abc% complete", nil},
+		{"Recovering, 43 percent", nil},
+	} {
+		data := `
+   Array A
+
+      Logical Drive: 1
+         Size: 558.9 GB
+         Status: ` + tc.status + `
+`
+		reg := prometheus.NewPedanticRegistry()
+		reg.MustRegister(NewSsacliLogDiskCollectorWithData("1", "0", data, Options{}))
+		families, err := reg.Gather()
+		if err != nil {
+			t.Fatalf("%q: Gather: %v", tc.status, err)
+		}
+
+		var got []float64
+		for _, mf := range families {
+			if mf.GetName() != "ssacli_log_disk_rebuild_progress_ratio" {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				got = append(got, m.GetGauge().GetValue())
+			}
+		}
+		if len(got) != len(tc.want) || (len(got) == 1 && got[0] != tc.want[0]) {
+			t.Errorf("%q: expected %v, got %v", tc.status, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+)
+
+// SmartResults collects the smartctl verdicts of a single scrape, so the
+// summary collector, which runs after the smartctl collectors, does not
+// have to run smartctl again. Results are keyed by drive serial number
+// because the cciss index smartctl is called with says nothing reliable
+// about which drive answered.
+type SmartResults struct {
+	mu   sync.Mutex
+	seen map[string]smartResult
+}
+
+type smartResult struct {
+	// healthFailed is set when "smartctl -H" reported anything but a pass
+	healthFailed bool
+	// reallocated counts reallocated sectors, or grown defects on SAS
+	// drives, nil when the drive reports neither
+	reallocated *float64
+}
+
+// NewSmartResults returns an empty result set for one scrape.
+func NewSmartResults() *SmartResults {
+	return &SmartResults{seen: make(map[string]smartResult)}
+}
+
+func (s *SmartResults) set(info parser.SmartctlDiskDataInfo, attrs parser.SmartctlDiskDataAttr) {
+	if s == nil || info.SN == "" {
+		return
+	}
+
+	r := smartResult{
+		healthFailed: info.Health != "" && info.Health != "PASSED" && info.Health != "OK",
+		reallocated:  attrs.ReallocatedSectorCt,
+	}
+	if r.reallocated == nil {
+		r.reallocated = attrs.GrownDefects
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[serialKey(info.SN)] = r
+}
+
+func (s *SmartResults) get(serialNumber string) (smartResult, bool) {
+	if s == nil || serialNumber == "" {
+		return smartResult{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.seen[serialKey(serialNumber)]
+	return r, ok
+}
+
+// serialKey normalises a serial number, ssacli and smartctl disagree on
+// case and padding for some drives.
+func serialKey(sn string) string {
+	return strings.ToUpper(strings.TrimSpace(sn))
+}
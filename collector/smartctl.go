@@ -12,9 +12,12 @@ import (
 var _ prometheus.Collector = &SmartctlDiskCollector{}
 
 type SmartctlDiskCollector struct {
-	diskID     string
-	diskN      int
-	devicePath string
+	diskID       string
+	serialNumber string
+	diskN        int
+	devicePath   string
+	results      *SmartResults
+	opts         Options
 
 	rawReadErrorRate      *prometheus.Desc
 	reallocatedSectorCt   *prometheus.Desc
@@ -43,6 +46,13 @@ type SmartctlDiskCollector struct {
 }
 
 func NewSmartctlDiskCollector(devicePath string, diskID string, diskN int) *SmartctlDiskCollector {
+	return NewSmartctlDiskCollectorWithResults(devicePath, diskID, "", diskN, nil, Options{})
+}
+
+// NewSmartctlDiskCollectorWithResults Create new collector that checks the
+// drive answering at cciss index diskN has the serial number ssacli
+// reported for diskID, and records its verdict in results
+func NewSmartctlDiskCollectorWithResults(devicePath string, diskID string, serialNumber string, diskN int, results *SmartResults, opts Options) *SmartctlDiskCollector {
 	var (
 		namespace = "smartctl"
 		subsystem = "physical_disk"
@@ -54,11 +64,18 @@ func NewSmartctlDiskCollector(devicePath string, diskID string, diskN int) *Smar
 			"fromFact",
 		}
 	)
+	// The legacy shape has no drive location labels
+	if !opts.Legacy {
+		labels = append(labels, "port", "port_type", "box", "bay")
+	}
 
 	return &SmartctlDiskCollector{
 		diskID:                diskID,
+		serialNumber:          serialNumber,
 		diskN:                 diskN,
 		devicePath:            devicePath,
+		results:               results,
+		opts:                  opts,
 		rawReadErrorRate:      prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "rawReadErrorRate"), "Smartctl raw read error rate", labels, nil),
 		reallocatedSectorCt:   prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "reallocatedSectorCt"), "Smartctl reallocated sector ct", labels, nil),
 		powerOnHours:          prometheus.NewDesc(prometheus.BuildFQName(namespace, subsystem, "powerOnHours"), "Smartctl power on hours", labels, nil),
@@ -116,7 +133,7 @@ func (c *SmartctlDiskCollector) collect(ch chan<- prometheus.Metric) (*prometheu
 	}
 
 	diskArg := fmt.Sprintf("cciss,%d", c.diskN)
-	cmd := exec.Command("smartctl", "-iA", "-d", diskArg, c.devicePath)
+	cmd := exec.Command("smartctl", "-iAH", "-d", diskArg, c.devicePath)
 	out, err := cmd.CombinedOutput()
 
 	data := parser.ParseSmartctlDisk(string(out))
@@ -134,7 +151,17 @@ func (c *SmartctlDiskCollector) collect(ch chan<- prometheus.Metric) (*prometheu
 	info := data.SmartctlDiskDataInfo[0]
 	attrs := data.SmartctlDiskDataAttr[0]
 
+	// Never attribute another drive's SMART data to this one
+	if c.serialNumber != "" && serialKey(info.SN) != serialKey(c.serialNumber) {
+		return nil, fmt.Errorf("%s reports serial number %q, expected %q", diskArg, info.SN, c.serialNumber)
+	}
+
+	c.results.set(info, attrs)
+
 	labels := []string{c.diskID, info.Model, info.SN, info.RotRate, info.FromFact}
+	if !c.opts.Legacy {
+		labels = append(labels, locationLabels(c.diskID)...)
+	}
 
 	sendMetric := func(desc *prometheus.Desc, val *float64) {
 		if desc != nil && val != nil {
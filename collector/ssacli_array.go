@@ -0,0 +1,200 @@
+package collector
+
+import (
+	"log"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SsacliArrayCollector{}
+
+// ArraySpares counts the spare drives assigned to an array
+type ArraySpares struct {
+	Total   int
+	Healthy int
+}
+
+// SsacliArrayCollector exports the state of a single array
+type SsacliArrayCollector struct {
+	arrayID string
+	slotID  string
+	rawData string
+	spares  *ArraySpares
+
+	statusDesc      *prometheus.Desc
+	unusedSpaceDesc *prometheus.Desc
+	infoDesc        *prometheus.Desc
+	stateDesc       *prometheus.Desc
+
+	spareDrivesDesc    *prometheus.Desc
+	noHealthySpareDesc *prometheus.Desc
+	smartPathDesc      *prometheus.Desc
+}
+
+// NewSsacliArrayCollector Create new collector
+func NewSsacliArrayCollector(arrayID string, slotID string) *SsacliArrayCollector {
+	return NewSsacliArrayCollectorWithData(arrayID, slotID, "", nil)
+}
+
+// NewSsacliArrayCollectorWithData Create new collector from pre-collected
+// "array all show detail" output. spares come from the physical drive
+// listing, spare metrics are left out when it is nil.
+func NewSsacliArrayCollectorWithData(arrayID string, slotID string, data string, spares *ArraySpares) *SsacliArrayCollector {
+	var (
+		namespace = "ssacli"
+		subsystem = "array"
+		labels    = []string{
+			"slot",
+			"array",
+		}
+		infoLabels = append(labels,
+			"interface_type",
+			"array_type",
+			"spare_type",
+		)
+	)
+
+	return &SsacliArrayCollector{
+		arrayID: arrayID,
+		slotID:  slotID,
+		rawData: data,
+		spares:  spares,
+		statusDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "status"),
+			"Hardware raid array status (1 if OK, 0 otherwise)",
+			labels,
+			nil,
+		),
+		unusedSpaceDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "unused_space_bytes"),
+			"Hardware raid array space not allocated to logical drives",
+			labels,
+			nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "info"),
+			"Hardware raid array configuration, always 1",
+			infoLabels,
+			nil,
+		),
+		stateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "state"),
+			"Hardware raid array state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		smartPathDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "smart_path_state"),
+			"Hardware raid array HPE SSD Smart Path state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		spareDrivesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "spare_drives"),
+			"Number of spare drives assigned to the hardware raid array",
+			labels,
+			nil,
+		),
+		noHealthySpareDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "no_healthy_spare"),
+			"1 if none of the spare drives assigned to the hardware raid array is OK, including arrays without spares",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SsacliArrayCollector) Describe(ch chan<- *prometheus.Desc) {
+	ds := []*prometheus.Desc{
+		c.statusDesc,
+		c.unusedSpaceDesc,
+		c.infoDesc,
+		c.stateDesc,
+		c.smartPathDesc,
+		c.spareDrivesDesc,
+		c.noHealthySpareDesc,
+	}
+	for _, d := range ds {
+		ch <- d
+	}
+}
+
+// Collect create collector
+func (c *SsacliArrayCollector) Collect(ch chan<- prometheus.Metric) {
+	if _, err := c.collect(ch); err != nil {
+		log.Printf("[ERROR] failed collecting array metrics for %s: %v", c.arrayID, err)
+	}
+}
+
+func (c *SsacliArrayCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
+	if c.arrayID == "" {
+		return nil, nil
+	}
+
+	var output string
+	if c.rawData != "" {
+		output = c.rawData
+	} else {
+		slotArg := "slot=" + c.slotID
+		out, err := RunSsacli("ctrl", slotArg, "array", c.arrayID, "show", "detail")
+		if err != nil {
+			return nil, err
+		}
+		output = string(out)
+	}
+
+	data := parser.ParseSsacliArray(output)
+	if data == nil {
+		return nil, nil
+	}
+
+	for i := range data.SsacliArrayData {
+		labels := []string{
+			c.slotID,
+			data.SsacliArrayData[i].ID,
+		}
+
+		val := 0.0
+		if data.SsacliArrayData[i].Status == "OK" {
+			val = 1.0
+		}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.statusDesc,
+			prometheus.GaugeValue,
+			val,
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.unusedSpaceDesc,
+			prometheus.GaugeValue,
+			data.SsacliArrayData[i].UnusedSpaceBytes,
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.infoDesc,
+			prometheus.GaugeValue,
+			1,
+			append(labels,
+				data.SsacliArrayData[i].InterfaceType,
+				data.SsacliArrayData[i].ArrayType,
+				data.SsacliArrayData[i].SpareType,
+			)...,
+		)
+		arrayStates.collect(ch, c.stateDesc, data.SsacliArrayData[i].Status, labels...)
+		smartPathStates.collect(ch, c.smartPathDesc, data.SsacliArrayData[i].SmartPath, labels...)
+
+		if c.spares != nil {
+			noHealthySpare := 0.0
+			if c.spares.Healthy == 0 {
+				noHealthySpare = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(c.spareDrivesDesc, prometheus.GaugeValue, float64(c.spares.Total), labels...)
+			ch <- prometheus.MustNewConstMetric(c.noHealthySpareDesc, prometheus.GaugeValue, noHealthySpare, labels...)
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"log"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SsacliBatteryCollector{}
+
+// SsacliBatteryCollector exports the battery/capacitor module of every
+// controller
+type SsacliBatteryCollector struct {
+	rawData string
+
+	statusDesc      *prometheus.Desc
+	countDesc       *prometheus.Desc
+	chargeStateDesc *prometheus.Desc
+	temperatureDesc *prometheus.Desc
+}
+
+// NewSsacliBatteryCollector Create new collector
+func NewSsacliBatteryCollector() *SsacliBatteryCollector {
+	return NewSsacliBatteryCollectorWithData("")
+}
+
+// NewSsacliBatteryCollectorWithData Create new collector from pre-collected
+// "ctrl all show detail" output
+func NewSsacliBatteryCollectorWithData(data string) *SsacliBatteryCollector {
+	var (
+		namespace = "ssacli"
+		subsystem = "controller"
+		labels    = []string{"slot"}
+	)
+
+	return &SsacliBatteryCollector{
+		rawData: data,
+		statusDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "battery_status"),
+			"Hardware raid controller battery/capacitor status, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		countDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "battery_count"),
+			"Number of batteries/capacitors installed on the controller",
+			labels,
+			nil,
+		),
+		chargeStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "battery_charge_state"),
+			"Hardware raid controller battery/capacitor charge state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		temperatureDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "battery_temperature_celsius"),
+			"Hardware raid controller battery/capacitor temperature",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SsacliBatteryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ds := []*prometheus.Desc{
+		c.statusDesc,
+		c.countDesc,
+		c.chargeStateDesc,
+		c.temperatureDesc,
+	}
+	for _, d := range ds {
+		ch <- d
+	}
+}
+
+// Collect create collector
+func (c *SsacliBatteryCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.collect(ch); err != nil {
+		log.Printf("[ERROR] failed collecting battery metrics: %v", err)
+	}
+}
+
+func (c *SsacliBatteryCollector) collect(ch chan<- prometheus.Metric) error {
+	output := c.rawData
+	if output == "" {
+		out, err := RunSsacli("ctrl", "all", "show", "detail")
+		if err != nil {
+			return err
+		}
+		output = string(out)
+	}
+
+	for _, b := range parser.ParseSsacliBattery(output).SsacliBatteryData {
+		batteryStates.collect(ch, c.statusDesc, b.Status, b.SlotID)
+		batteryChargeStates.collect(ch, c.chargeStateDesc, b.ChargeStatus, b.SlotID)
+
+		if b.Count != nil {
+			ch <- prometheus.MustNewConstMetric(c.countDesc, prometheus.GaugeValue, *b.Count, b.SlotID)
+		}
+		if b.Temperature != nil {
+			ch <- prometheus.MustNewConstMetric(c.temperatureDesc, prometheus.GaugeValue, *b.Temperature, b.SlotID)
+		}
+	}
+
+	return nil
+}
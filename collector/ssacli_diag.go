@@ -0,0 +1,195 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SsacliDiagCollector{}
+
+// diagGenerator makes sure only one "ctrl all diag" runs at a time. The
+// report takes minutes to generate, far longer than a scrape may take,
+// so it runs in the background and scrapes read the previous report.
+type diagGenerator struct {
+	mu      sync.Mutex
+	running bool
+}
+
+var diag = &diagGenerator{}
+
+// refresh starts regenerating the report at path unless it is newer than
+// interval or a run is already in progress.
+func (g *diagGenerator) refresh(path string, interval time.Duration) {
+	if st, err := os.Stat(path); err == nil && time.Since(st.ModTime()) < interval {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.running {
+		return
+	}
+	g.running = true
+
+	go func() {
+		defer func() {
+			g.mu.Lock()
+			g.running = false
+			g.mu.Unlock()
+		}()
+		if err := generateDiag(path); err != nil {
+			log.Printf("[ERROR] failed generating diag report %s: %v", path, err)
+		}
+	}()
+}
+
+// diagTimeout bounds a single "ctrl all diag" run
+const diagTimeout = 30 * time.Minute
+
+// generateDiag writes the report next to path and moves it in place, so a
+// scrape never reads a half written archive.
+//
+// The run takes minutes. It holds the ssacli lock like every other run, but
+// marked busy, so the runs of scrapes meanwhile are skipped instead of
+// waiting for it until they time out.
+func generateDiag(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), diagTimeout)
+	defer cancel()
+
+	tmp := strings.TrimSuffix(path, ".zip") + ".tmp.zip"
+	out, err := tool.run(withBusy(ctx, "generating the diagnostic report"), "ctrl", "all", "diag", "file="+tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return os.Rename(tmp, path)
+}
+
+// SsacliDiagCollector exports the drive error counters found in the HPE
+// Array Diagnostic Utility report
+type SsacliDiagCollector struct {
+	opts Options
+
+	reportTimeDesc      *prometheus.Desc
+	phyErrorsDesc       *prometheus.Desc
+	errorLogEntriesDesc *prometheus.Desc
+}
+
+// NewSsacliDiagCollector Create new collector
+func NewSsacliDiagCollector(opts Options) *SsacliDiagCollector {
+	var (
+		namespace = "ssacli"
+		labels    = []string{"physDiskID", "physDiskSlotID", "port", "port_type", "box", "bay"}
+	)
+	if opts.Legacy {
+		labels = []string{"physDiskID", "physDiskSlotID"}
+	}
+
+	return &SsacliDiagCollector{
+		opts: opts,
+		reportTimeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "diag", "report_timestamp_seconds"),
+			"Time the diagnostic report was generated",
+			nil,
+			nil,
+		),
+		phyErrorsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "phys_disk", "phy_errors_total"),
+			"SAS PHY error counters of the physical drive from the diagnostic report",
+			append(labels, "phy", "counter"),
+			nil,
+		),
+		errorLogEntriesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "phys_disk", "error_log_entries"),
+			"Entries in the physical drive error log from the diagnostic report",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SsacliDiagCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.reportTimeDesc
+	ch <- c.phyErrorsDesc
+	ch <- c.errorLogEntriesDesc
+}
+
+// Collect create collector
+func (c *SsacliDiagCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.collect(ch); err != nil {
+		log.Printf("[ERROR] failed collecting diag report %s: %v", c.opts.DiagFile, err)
+	}
+}
+
+func (c *SsacliDiagCollector) collect(ch chan<- prometheus.Metric) error {
+	if c.opts.DiagFile == "" {
+		return nil
+	}
+	if c.opts.DiagInterval > 0 {
+		diag.refresh(c.opts.DiagFile, c.opts.DiagInterval)
+	}
+
+	f, err := os.Open(c.opts.DiagFile)
+	if err != nil {
+		if os.IsNotExist(err) && c.opts.DiagInterval > 0 {
+			// The first report is still being generated
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	report, err := parser.ParseADUArchive(f, st.Size())
+	if err != nil {
+		return err
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.reportTimeDesc, prometheus.GaugeValue, float64(st.ModTime().Unix()))
+
+	// The report can list a drive more than once, e.g. under its port and
+	// its enclosure. Duplicate series would fail the whole scrape, so only
+	// the first listing is exported.
+	type driveKey struct{ slot, id string }
+	seen := make(map[driveKey]bool)
+
+	for _, ctrl := range report.Controllers {
+		for _, d := range ctrl.Drives {
+			if seen[driveKey{ctrl.Slot, d.ID}] {
+				continue
+			}
+			seen[driveKey{ctrl.Slot, d.ID}] = true
+
+			labels := []string{d.ID, ctrl.Slot}
+			if !c.opts.Legacy {
+				labels = append(labels, locationLabels(d.ID)...)
+			}
+			counters := make(map[string]bool)
+			for _, p := range d.PHYErrors {
+				phy := strconv.Itoa(p.PHY)
+				if counters[phy+"/"+p.Counter] {
+					continue
+				}
+				counters[phy+"/"+p.Counter] = true
+				ch <- prometheus.MustNewConstMetric(c.phyErrorsDesc, prometheus.CounterValue, p.Value,
+					append(labels, phy, p.Counter)...)
+			}
+			ch <- prometheus.MustNewConstMetric(c.errorLogEntriesDesc, prometheus.GaugeValue, float64(d.ErrorLogEntries), labels...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package collector
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSsacliDiagCollectorDuplicateDrives(t *testing.T) {
+	// Drive 1I:1:1 is listed under the controller and again under its
+	// enclosure
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<ADUReport>
+  <Device deviceType="ArrayController" marketingName="Smart Array P440ar">
+    <MetaProperty id="Slot" value="0"/>
+    <Device deviceType="PhysicalDrive" marketingName="Physical Drive 1I:1:1">
+      <Property id="PHY 1 Invalid DWORD Count" value="0x00000002"/>
+    </Device>
+    <Device deviceType="Storage Enclosure" marketingName="Internal Drive Cage at Port 1I, Box 1">
+      <Device deviceType="PhysicalDrive" marketingName="Physical Drive 1I:1:1">
+        <Property id="PHY 1 Invalid DWORD Count" value="0x00000002"/>
+      </Device>
+    </Device>
+  </Device>
+</ADUReport>
+`
+
+	file := filepath.Join(t.TempDir(), "adu.zip")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("ADUReport.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(report)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewSsacliDiagCollector(Options{DiagFile: file}))
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	for _, mf := range families {
+		if mf.GetName() == "ssacli_phys_disk_phy_errors_total" && len(mf.GetMetric()) != 1 {
+			t.Errorf("expected 1 PHY error series, got %d", len(mf.GetMetric()))
+		}
+	}
+}
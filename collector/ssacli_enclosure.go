@@ -0,0 +1,167 @@
+package collector
+
+import (
+	"log"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SsacliEnclosureCollector{}
+
+// SsacliEnclosureCollector exports the drive cages and external
+// enclosures attached to a controller
+type SsacliEnclosureCollector struct {
+	slotID  string
+	rawData string
+
+	infoDesc             *prometheus.Desc
+	stateDesc            *prometheus.Desc
+	fanStateDesc         *prometheus.Desc
+	powerSupplyStateDesc *prometheus.Desc
+	temperatureStateDesc *prometheus.Desc
+	temperatureDesc      *prometheus.Desc
+	driveBaysDesc        *prometheus.Desc
+}
+
+// NewSsacliEnclosureCollector Create new collector
+func NewSsacliEnclosureCollector(slotID string) *SsacliEnclosureCollector {
+	return NewSsacliEnclosureCollectorWithData(slotID, "")
+}
+
+// NewSsacliEnclosureCollectorWithData Create new collector from
+// pre-collected "enclosure all show detail" output
+func NewSsacliEnclosureCollectorWithData(slotID string, data string) *SsacliEnclosureCollector {
+	var (
+		namespace = "ssacli"
+		subsystem = "enclosure"
+		labels    = []string{
+			"slot",
+			"port",
+			"box",
+		}
+		infoLabels = append(labels,
+			"name",
+			"location",
+			"vendor",
+			"serial_number",
+			"firmware",
+		)
+	)
+
+	return &SsacliEnclosureCollector{
+		slotID:  slotID,
+		rawData: data,
+		infoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "info"),
+			"Drive cage or storage enclosure identity, always 1",
+			infoLabels,
+			nil,
+		),
+		stateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "state"),
+			"Drive cage or storage enclosure state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		fanStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "fan_state"),
+			"Enclosure fan state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		powerSupplyStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "power_supply_state"),
+			"Enclosure power supply state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		temperatureStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "temperature_state"),
+			"Enclosure temperature state, 1 for the current state and 0 for all others",
+			append(labels, "state"),
+			nil,
+		),
+		temperatureDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "temperature_celsius"),
+			"Enclosure temperature sensor reading",
+			append(labels, "sensor"),
+			nil,
+		),
+		driveBaysDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "drive_bays"),
+			"Number of drive bays in the enclosure",
+			labels,
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SsacliEnclosureCollector) Describe(ch chan<- *prometheus.Desc) {
+	ds := []*prometheus.Desc{
+		c.infoDesc,
+		c.stateDesc,
+		c.fanStateDesc,
+		c.powerSupplyStateDesc,
+		c.temperatureStateDesc,
+		c.temperatureDesc,
+		c.driveBaysDesc,
+	}
+	for _, d := range ds {
+		ch <- d
+	}
+}
+
+// Collect create collector
+func (c *SsacliEnclosureCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.collect(ch); err != nil {
+		log.Printf("[ERROR] failed collecting enclosure metrics for slot %s: %v", c.slotID, err)
+	}
+}
+
+func (c *SsacliEnclosureCollector) collect(ch chan<- prometheus.Metric) error {
+	if c.slotID == "" {
+		return nil
+	}
+
+	output := c.rawData
+	if output == "" {
+		out, err := RunSsacli("ctrl", "slot="+c.slotID, "enclosure", "all", "show", "detail")
+		if err != nil {
+			return err
+		}
+		output = string(out)
+	}
+
+	for _, e := range parser.ParseSsacliEnclosure(output).SsacliEnclosureData {
+		labels := []string{c.slotID, e.Port, e.Box}
+
+		ch <- prometheus.MustNewConstMetric(
+			c.infoDesc,
+			prometheus.GaugeValue,
+			1,
+			append(labels,
+				e.Name,
+				e.Location,
+				e.VendorID,
+				e.SerialNumber,
+				e.Firmware,
+			)...,
+		)
+
+		enclosureStates.collect(ch, c.stateDesc, e.Status, labels...)
+		enclosureStates.collect(ch, c.fanStateDesc, e.FanStatus, labels...)
+		enclosureStates.collect(ch, c.temperatureStateDesc, e.TemperatureStatus, labels...)
+		powerSupplyStates.collect(ch, c.powerSupplyStateDesc, e.PowerSupplyStatus, labels...)
+
+		for sensor, t := range e.Temperatures {
+			ch <- prometheus.MustNewConstMetric(c.temperatureDesc, prometheus.GaugeValue, t, append(labels, sensor)...)
+		}
+		if e.DriveBays != nil {
+			ch <- prometheus.MustNewConstMetric(c.driveBaysDesc, prometheus.GaugeValue, *e.DriveBays, labels...)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package collector
+
+import (
+	"log"
+	"sync"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SsacliEventsCollector{}
+
+// eventTracker remembers the entries of the last log read from every
+// controller and the running totals derived from the events seen so far.
+// Collectors are created for every scrape, so this has to outlive them.
+type eventTracker struct {
+	mu    sync.Mutex
+	slots map[string]*eventCursor
+}
+
+type eventCursor struct {
+	// last counts the entries of the previous log by identity
+	last     map[eventID]int
+	lastTime float64
+	totals   map[eventKey]float64
+}
+
+type eventKey struct {
+	class    string
+	severity string
+}
+
+// eventID identifies a log entry. Not every firmware numbers its entries
+// and the log drops the oldest ones when full, so neither the number nor
+// the position alone tells whether an entry was seen before.
+type eventID struct {
+	seq         int64
+	timestamp   string
+	class       string
+	description string
+}
+
+var events = &eventTracker{slots: make(map[string]*eventCursor)}
+
+// update counts the entries that were not in the previous log read from a
+// controller and returns them. The first time a controller is seen its
+// whole log is counted but nothing is returned, so a restart does not
+// replay old events.
+func (t *eventTracker) update(slot string, data []parser.SsacliEventData) (*eventCursor, []parser.SsacliEventData) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cur, seen := t.slots[slot]
+	if !seen {
+		cur = &eventCursor{totals: make(map[eventKey]float64)}
+		t.slots[slot] = cur
+	}
+
+	var (
+		fresh   []parser.SsacliEventData
+		current = make(map[eventID]int, len(data))
+	)
+	for _, e := range data {
+		id := eventID{e.Seq, e.Timestamp, e.Class, e.Description}
+		current[id]++
+		// Identical entries are told apart by how often they occur
+		if current[id] <= cur.last[id] {
+			continue
+		}
+		cur.totals[eventKey{e.Class, e.Severity}]++
+		if !e.Time.IsZero() {
+			cur.lastTime = max(cur.lastTime, float64(e.Time.Unix()))
+		}
+		if seen {
+			fresh = append(fresh, e)
+		}
+	}
+	cur.last = current
+
+	// Hand out a copy, the cursor keeps changing under the lock
+	snapshot := &eventCursor{lastTime: cur.lastTime, totals: make(map[eventKey]float64, len(cur.totals))}
+	for k, v := range cur.totals {
+		snapshot.totals[k] = v
+	}
+	return snapshot, fresh
+}
+
+// SsacliEventsCollector exports the controller event log
+type SsacliEventsCollector struct {
+	slotID  string
+	rawData string
+	opts    Options
+
+	eventsDesc        *prometheus.Desc
+	lastEventTimeDesc *prometheus.Desc
+}
+
+// NewSsacliEventsCollector Create new collector
+func NewSsacliEventsCollector(slotID string, opts Options) *SsacliEventsCollector {
+	return NewSsacliEventsCollectorWithData(slotID, "", opts)
+}
+
+// NewSsacliEventsCollectorWithData Create new collector from pre-collected
+// "show events" output
+func NewSsacliEventsCollectorWithData(slotID string, data string, opts Options) *SsacliEventsCollector {
+	var (
+		namespace = "ssacli"
+		subsystem = "controller"
+	)
+
+	return &SsacliEventsCollector{
+		slotID:  slotID,
+		rawData: data,
+		opts:    opts,
+		eventsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "events_total"),
+			"Controller event log entries seen since the exporter started",
+			[]string{"slot", "class", "severity"},
+			nil,
+		),
+		lastEventTimeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "last_event_timestamp_seconds"),
+			"Time of the newest controller event log entry",
+			[]string{"slot"},
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SsacliEventsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.eventsDesc
+	ch <- c.lastEventTimeDesc
+}
+
+// Collect create collector
+func (c *SsacliEventsCollector) Collect(ch chan<- prometheus.Metric) {
+	if err := c.collect(ch); err != nil {
+		log.Printf("[ERROR] failed collecting event log for slot %s: %v", c.slotID, err)
+	}
+}
+
+func (c *SsacliEventsCollector) collect(ch chan<- prometheus.Metric) error {
+	if c.slotID == "" {
+		return nil
+	}
+
+	output := c.rawData
+	if output == "" {
+		out, err := RunSsacli("ctrl", "slot="+c.slotID, "show", "events")
+		if err != nil {
+			return err
+		}
+		output = string(out)
+	}
+
+	cur, fresh := events.update(c.slotID, parser.ParseSsacliEvents(output).SsacliEventData)
+
+	if c.opts.LogEvents {
+		for _, e := range fresh {
+			log.Printf("[EVENT] slot=%s seq=%d time=%q class=%q severity=%q description=%q",
+				c.slotID, e.Seq, e.Timestamp, e.Class, e.Severity, e.Description)
+		}
+	}
+
+	for k, total := range cur.totals {
+		ch <- prometheus.MustNewConstMetric(c.eventsDesc, prometheus.CounterValue, total, c.slotID, k.class, k.severity)
+	}
+	if cur.lastTime > 0 {
+		ch <- prometheus.MustNewConstMetric(c.lastEventTimeDesc, prometheus.GaugeValue, cur.lastTime, c.slotID)
+	}
+
+	return nil
+}
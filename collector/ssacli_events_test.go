@@ -0,0 +1,50 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+)
+
+func unnumberedEvent(ts string, desc string) parser.SsacliEventData {
+	return parser.SsacliEventData{Timestamp: ts, Class: "Physical Drive", Severity: "Warning", Description: desc}
+}
+
+func TestEventTrackerWrappedLog(t *testing.T) {
+	tr := &eventTracker{slots: make(map[string]*eventCursor)}
+
+	a := unnumberedEvent("2024-01-15 08:00:00", "a")
+	b := unnumberedEvent("2024-01-15 09:00:00", "b")
+	c := unnumberedEvent("2024-01-15 10:00:00", "c")
+	d := unnumberedEvent("2024-01-15 11:00:00", "d")
+
+	cur, fresh := tr.update("0", []parser.SsacliEventData{a, b, c})
+	if len(fresh) != 0 {
+		t.Errorf("first read should not return events, got %v", fresh)
+	}
+	key := eventKey{"Physical Drive", "Warning"}
+	if cur.totals[key] != 3 {
+		t.Errorf("expected 3 events counted, got %v", cur.totals[key])
+	}
+
+	// The full log dropped its oldest entry to make room for d
+	cur, fresh = tr.update("0", []parser.SsacliEventData{b, c, d})
+	if len(fresh) != 1 || fresh[0].Description != "d" {
+		t.Errorf("expected only d to be new, got %v", fresh)
+	}
+	if cur.totals[key] != 4 {
+		t.Errorf("expected 4 events counted, got %v", cur.totals[key])
+	}
+
+	// A repeat of an entry already in the log is still a new event
+	cur, fresh = tr.update("0", []parser.SsacliEventData{c, d, d})
+	if len(fresh) != 1 || cur.totals[key] != 5 {
+		t.Errorf("expected the repeated d to be new, got %v and %v", fresh, cur.totals[key])
+	}
+
+	// Nothing changed
+	cur, fresh = tr.update("0", []parser.SsacliEventData{c, d, d})
+	if len(fresh) != 0 || cur.totals[key] != 5 {
+		t.Errorf("expected no new events, got %v and %v", fresh, cur.totals[key])
+	}
+}
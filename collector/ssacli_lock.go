@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// lockWaitBuckets are the upper bounds of the lock wait histogram in
+// seconds. Runs give up waiting after the run timeout, 30s by default.
+var lockWaitBuckets = []float64{0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// ssacliLock serializes the ssacli runs of the exporter, which may overlap
+// between concurrent scrapes. With a lock file it also excludes other
+// tools that flock the same file.
+type ssacliLock struct {
+	// sem holds a token while a run is in progress. Unlike a mutex,
+	// waiting for it can be given up.
+	sem  chan struct{}
+	file string
+
+	// The wait statistics have their own mutex, so they can be read while
+	// a run holds sem. busy names the long run holding sem, if any.
+	statsMu sync.Mutex
+	busy    string
+	waiting int
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+type lockStats struct {
+	waiting int
+	count   uint64
+	sum     float64
+	buckets map[float64]uint64
+}
+
+var ssacliRuns = newSsacliLock()
+
+func newSsacliLock() *ssacliLock {
+	return &ssacliLock{sem: make(chan struct{}, 1), buckets: make(map[float64]uint64)}
+}
+
+// SetLockFile makes every ssacli run also hold an exclusive flock on path,
+// empty only serializes within the exporter. It is meant to be called
+// before the exporter is registered.
+func SetLockFile(path string) {
+	ssacliRuns.file = path
+}
+
+// busyKey marks the context of a run taking minutes, see withBusy.
+type busyKey struct{}
+
+// withBusy marks a run taking minutes, described by what. While it holds
+// the lock other runs are skipped at once instead of waiting for it.
+func withBusy(ctx context.Context, what string) context.Context {
+	return context.WithValue(ctx, busyKey{}, what)
+}
+
+// acquire blocks until no other ssacli run is in progress and returns the
+// function releasing the lock. It gives up when ctx is done, so runs do not
+// pile up behind one that hangs, and right away while a run marked with
+// withBusy holds the lock.
+func (l *ssacliLock) acquire(ctx context.Context) (func(), error) {
+	if what := l.busyWith(); what != "" {
+		return nil, fmt.Errorf("skipped, ssacli is busy %s", what)
+	}
+
+	start := time.Now()
+	l.setWaiting(1)
+	defer l.setWaiting(-1)
+	// Runs that gave up waiting are observed too, they are the ones the
+	// histogram is for
+	defer func() { l.observe(time.Since(start).Seconds()) }()
+
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for other ssacli run: %w", ctx.Err())
+	}
+
+	var f *os.File
+	if l.file != "" {
+		var err error
+		if f, err = lockFile(ctx, l.file); err != nil {
+			<-l.sem
+			return nil, fmt.Errorf("locking %s: %w", l.file, err)
+		}
+	}
+
+	what, _ := ctx.Value(busyKey{}).(string)
+	l.setBusy(what)
+
+	return func() {
+		l.setBusy("")
+		if f != nil {
+			unlockFile(f)
+		}
+		<-l.sem
+	}, nil
+}
+
+func (l *ssacliLock) busyWith() string {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+	return l.busy
+}
+
+func (l *ssacliLock) setBusy(what string) {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+	l.busy = what
+}
+
+func (l *ssacliLock) setWaiting(delta int) {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+	l.waiting += delta
+}
+
+func (l *ssacliLock) observe(seconds float64) {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+
+	l.count++
+	l.sum += seconds
+	for _, b := range lockWaitBuckets {
+		if seconds <= b {
+			l.buckets[b]++
+		}
+	}
+}
+
+// stats returns a copy of the wait statistics
+func (l *ssacliLock) stats() lockStats {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+
+	s := lockStats{waiting: l.waiting, count: l.count, sum: l.sum, buckets: make(map[float64]uint64, len(l.buckets))}
+	for b, n := range l.buckets {
+		s.buckets[b] = n
+	}
+	return s
+}
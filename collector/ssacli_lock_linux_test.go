@@ -0,0 +1,34 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSsacliLockFileTimeout(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ssacli.lock")
+
+	// Another process holding the file lock
+	f, err := lockFile(context.Background(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlockFile(f)
+
+	l := newSsacliLock()
+	l.file = file
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the file lock to time out, got %v", err)
+	}
+
+	// The exporter's own lock was given back
+	if len(l.sem) != 0 {
+		t.Error("a timed out file lock kept the exporter lock")
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !linux
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+func lockFile(ctx context.Context, path string) (*os.File, error) {
+	return nil, errors.New("lock files are only supported on Linux")
+}
+
+func unlockFile(f *os.File) {
+	f.Close()
+}
@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSsacliLockHistogram(t *testing.T) {
+	l := newSsacliLock()
+	for _, s := range []float64{0.005, 0.2, 4, 400} {
+		l.observe(s)
+	}
+
+	s := l.stats()
+	if s.count != 4 || s.sum != 404.205 {
+		t.Errorf("expected 4 observations summing to 404.205, got %d and %v", s.count, s.sum)
+	}
+	// Buckets are cumulative, 400s falls into none of them
+	for b, want := range map[float64]uint64{0.01: 1, 0.1: 1, 0.5: 2, 1: 2, 2.5: 2, 5: 3, 300: 3} {
+		if s.buckets[b] != want {
+			t.Errorf("bucket %v: expected %d, got %d", b, want, s.buckets[b])
+		}
+	}
+}
+
+func TestSsacliLockWaiting(t *testing.T) {
+	l := newSsacliLock()
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		r, err := l.acquire(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- r
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for l.stats().waiting != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("second run never started waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-acquired:
+		t.Fatal("second run acquired a held lock")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	release()
+	(<-acquired)()
+
+	if s := l.stats(); s.waiting != 0 || s.count != 2 {
+		t.Errorf("expected no waiting runs and 2 observations, got %d and %d", s.waiting, s.count)
+	}
+}
+
+func TestSsacliLockTimeout(t *testing.T) {
+	l := newSsacliLock()
+
+	release, err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the wait to time out, got %v", err)
+	}
+
+	// The run that gave up is observed and no longer waiting
+	if s := l.stats(); s.waiting != 0 || s.count != 2 || s.sum < 0.02 {
+		t.Errorf("unexpected stats after timeout: %+v", s)
+	}
+}
+
+func TestSsacliLockBusy(t *testing.T) {
+	l := newSsacliLock()
+
+	release, err := l.acquire(withBusy(context.Background(), "generating the diagnostic report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other runs are skipped instead of waiting for the busy run
+	start := time.Now()
+	if _, err := l.acquire(context.Background()); err == nil || err.Error() != "skipped, ssacli is busy generating the diagnostic report" {
+		t.Fatalf("expected the run to be skipped, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("skipping took %v", time.Since(start))
+	}
+
+	release()
+	release, err = l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("lock still busy after the busy run finished: %v", err)
+	}
+	release()
+}
@@ -2,7 +2,8 @@ package collector
 
 import (
 	"log"
-	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,43 +15,162 @@ type SsacliLogDiskCollector struct {
 	diskID            string
 	slotID            string
 	rawData           string
+	opts              Options
 	logDiskStatusDesc *prometheus.Desc
+	logDiskStateDesc  *prometheus.Desc
+	logDiskInfoDesc   *prometheus.Desc
+	accelerationDesc  *prometheus.Desc
+
+	sizeDesc *prometheus.Desc
+	umeDesc  *prometheus.Desc
+
+	stripSizeDesc      *prometheus.Desc
+	fullStripeSizeDesc *prometheus.Desc
+	parityGroupsDesc   *prometheus.Desc
+
+	rebuildProgressDesc    *prometheus.Desc
+	parityInitProgressDesc *prometheus.Desc
+	transformProgressDesc  *prometheus.Desc
+	progressETADesc        *prometheus.Desc
 }
 
-func NewSsacliLogDiskCollector(diskID string, slotID string) *SsacliLogDiskCollector {
-	return NewSsacliLogDiskCollectorWithData(diskID, slotID, "")
+func NewSsacliLogDiskCollector(diskID string, slotID string, opts Options) *SsacliLogDiskCollector {
+	return NewSsacliLogDiskCollectorWithData(diskID, slotID, "", opts)
 }
 
-func NewSsacliLogDiskCollectorWithData(diskID string, slotID string, data string) *SsacliLogDiskCollector {
+func NewSsacliLogDiskCollectorWithData(diskID string, slotID string, data string, opts Options) *SsacliLogDiskCollector {
 	var (
-		namespace = "ssacli"
-		subsystem = "log_disk"
-		labels    = []string{
+		namespace    = "ssacli"
+		subsystem    = "log_disk"
+		legacyLabels = []string{
 			"logDiskID",
 			"logDiskSize",
 			"logDiskFaultTolerance",
 			"logDiskStatus",
 			"logDiskCaching",
-			"logDiskUME",
 			"logDiskSlotID",
 		}
+		idLabels   = logDiskLabels(opts)
+		infoLabels = append(idLabels,
+			"logDiskFaultTolerance",
+			"logDiskCaching",
+			"device",
+			"mount_points",
+			"unique_identifier",
+			"label",
+		)
 	)
 
+	statusLabels := idLabels
+	if opts.Legacy {
+		statusLabels = legacyLabels
+	}
+
 	return &SsacliLogDiskCollector{
 		diskID:  diskID,
 		slotID:  slotID,
 		rawData: data,
+		opts:    opts,
 		logDiskStatusDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, subsystem, "status"),
 			"Hardware raid logical drive status (1 if OK, 0 otherwise)",
-			labels,
+			statusLabels,
+			nil,
+		),
+		logDiskInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "info"),
+			"Hardware raid logical drive configuration and OS block device, always 1",
+			infoLabels,
+			nil,
+		),
+		sizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "size_bytes"),
+			"Hardware raid logical drive capacity",
+			idLabels,
+			nil,
+		),
+		stripSizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "strip_size_bytes"),
+			"Hardware raid logical drive strip size, the data written to each drive before moving to the next",
+			idLabels,
+			nil,
+		),
+		fullStripeSizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "full_stripe_size_bytes"),
+			"Hardware raid logical drive full stripe size, the data written across all data drives",
+			idLabels,
+			nil,
+		),
+		parityGroupsDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "parity_groups"),
+			"Number of parity groups of a RAID 50 or RAID 60 logical drive",
+			idLabels,
+			nil,
+		),
+		umeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "unrecoverable_media_errors"),
+			"Hardware raid logical drive unrecoverable media errors",
+			idLabels,
+			nil,
+		),
+		logDiskStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "state"),
+			"Hardware raid logical drive state, 1 for the current state and 0 for all others",
+			append(idLabels, "state"),
+			nil,
+		),
+		accelerationDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "acceleration_method"),
+			"Hardware raid logical drive acceleration method, 1 for the current method and 0 for all others",
+			append(idLabels, "method"),
+			nil,
+		),
+		rebuildProgressDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "rebuild_progress_ratio"),
+			"Hardware raid logical drive rebuild progress (0-1), only while recovering",
+			idLabels,
+			nil,
+		),
+		parityInitProgressDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "parity_init_progress_ratio"),
+			"Hardware raid logical drive parity initialization progress (0-1), only while initializing",
+			idLabels,
+			nil,
+		),
+		transformProgressDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "transform_progress_ratio"),
+			"Hardware raid logical drive transformation or expansion progress (0-1), only while transforming",
+			idLabels,
+			nil,
+		),
+		progressETADesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "progress_eta_seconds"),
+			"Estimated seconds until a running operation completes, based on the rate observed since the exporter first saw it",
+			append(idLabels, "operation"),
 			nil,
 		),
 	}
 }
 
 func (c *SsacliLogDiskCollector) Describe(ch chan<- *prometheus.Desc) {
-	ch <- c.logDiskStatusDesc
+	ds := []*prometheus.Desc{
+		c.logDiskStatusDesc,
+		c.logDiskStateDesc,
+		c.logDiskInfoDesc,
+		c.accelerationDesc,
+		c.sizeDesc,
+		c.stripSizeDesc,
+		c.fullStripeSizeDesc,
+		c.parityGroupsDesc,
+		c.umeDesc,
+		c.rebuildProgressDesc,
+		c.parityInitProgressDesc,
+		c.transformProgressDesc,
+		c.progressETADesc,
+	}
+	for _, d := range ds {
+		ch <- d
+	}
 }
 
 func (c *SsacliLogDiskCollector) Collect(ch chan<- prometheus.Metric) {
@@ -74,7 +194,7 @@ func (c *SsacliLogDiskCollector) collect(ch chan<- prometheus.Metric) (*promethe
 		output = c.rawData
 	} else {
 		slotArg := "slot=" + c.slotID
-		out, err := exec.Command("ssacli", "ctrl", slotArg, "ld", c.diskID, "show").CombinedOutput()
+		out, err := RunSsacli("ctrl", slotArg, "ld", c.diskID, "show")
 		if err != nil {
 			return nil, err
 		}
@@ -87,18 +207,23 @@ func (c *SsacliLogDiskCollector) collect(ch chan<- prometheus.Metric) (*promethe
 	}
 
 	for i := range data.SsacliLogDiskData {
-		labels := []string{
-			c.diskID,
-			data.SsacliLogDiskData[i].Size,
-			data.SsacliLogDiskData[i].FaultTolerance,
-			data.SsacliLogDiskData[i].Status,
-			data.SsacliLogDiskData[i].Caching,
-			data.SsacliLogDiskData[i].UME,
-			c.slotID,
+		disk := data.SsacliLogDiskData[i]
+		idLabels := logDiskLabelValues(c.opts, c.diskID, c.slotID, disk.Array)
+
+		statusLabels := idLabels
+		if c.opts.Legacy {
+			statusLabels = []string{
+				c.diskID,
+				disk.Size,
+				disk.FaultTolerance,
+				disk.Status,
+				disk.Caching,
+				c.slotID,
+			}
 		}
 
 		val := 0.0
-		if data.SsacliLogDiskData[i].Status == "OK" {
+		if disk.Status == "OK" {
 			val = 1.0
 		}
 
@@ -106,9 +231,82 @@ func (c *SsacliLogDiskCollector) collect(ch chan<- prometheus.Metric) (*promethe
 			c.logDiskStatusDesc,
 			prometheus.GaugeValue,
 			val,
-			labels...,
+			statusLabels...,
 		)
+
+		logDiskStates.collect(ch, c.logDiskStateDesc, disk.Status, idLabels...)
+		accelerationMethods.collect(ch, c.accelerationDesc, disk.Acceleration, idLabels...)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.logDiskInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			append(idLabels,
+				disk.FaultTolerance,
+				disk.Caching,
+				disk.Device,
+				strings.Join(disk.MountPoints, ","),
+				disk.UID,
+				disk.LID,
+			)...,
+		)
+
+		// Older ssacli versions print the full stripe as "Data Stripe Size"
+		fullStripe := disk.FullStripeSizeBytes
+		if fullStripe == nil {
+			fullStripe = disk.DataStripeSizeBytes
+		}
+
+		for _, g := range []struct {
+			desc *prometheus.Desc
+			val  *float64
+		}{
+			{c.sizeDesc, disk.SizeBytes},
+			{c.umeDesc, disk.UnrecoverableMediaErrors},
+			{c.stripSizeDesc, disk.StripSizeBytes},
+			{c.fullStripeSizeDesc, fullStripe},
+			{c.parityGroupsDesc, disk.ParityGroups},
+		} {
+			if g.val != nil {
+				ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, *g.val, idLabels...)
+			}
+		}
+
+		c.collectProgress(ch, c.rebuildProgressDesc, "rebuild", disk.RebuildProgress, idLabels)
+		c.collectProgress(ch, c.parityInitProgressDesc, "parity_init", disk.ParityInitProgress, idLabels)
+		c.collectProgress(ch, c.transformProgressDesc, "transform", disk.TransformProgress, idLabels)
 	}
 
 	return nil, nil
 }
+
+// logDiskLabels are the labels identifying a logical drive on the
+// ssacli_log_disk_* metrics. The legacy shape only has the v1 ID labels.
+func logDiskLabels(opts Options) []string {
+	if opts.Legacy {
+		return []string{"logDiskID", "logDiskSlotID"}
+	}
+	return []string{"logDiskID", "logDiskSlotID", "array"}
+}
+
+// logDiskLabelValues returns the values of logDiskLabels for a drive.
+func logDiskLabelValues(opts Options, diskID string, slotID string, array string) []string {
+	if opts.Legacy {
+		return []string{diskID, slotID}
+	}
+	return []string{diskID, slotID, array}
+}
+
+func (c *SsacliLogDiskCollector) collectProgress(ch chan<- prometheus.Metric, desc *prometheus.Desc, operation string, ratio *float64, labels []string) {
+	key := c.slotID + "/" + c.diskID + "/" + operation
+	if ratio == nil {
+		progress.forget(key)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *ratio, labels...)
+
+	if eta, ok := progress.eta(key, *ratio, time.Now()); ok {
+		ch <- prometheus.MustNewConstMetric(c.progressETADesc, prometheus.GaugeValue, eta, append(labels, operation)...)
+	}
+}
@@ -3,9 +3,12 @@ package collector
 import (
 	"fmt"
 	"log"
-	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/pkg/smartarray"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -15,18 +18,46 @@ type SsacliPhysDiskCollector struct {
 	diskID             string
 	slotID             string
 	rawData            string
+	controllerLinkRate float64
+	opts               Options
 	physDiskStatusDesc *prometheus.Desc
+	physDiskStateDesc  *prometheus.Desc
+	physDiskInfoDesc   *prometheus.Desc
+
+	temperatureDesc    *prometheus.Desc
+	maxTemperatureDesc *prometheus.Desc
+	tempThresholdDesc  *prometheus.Desc
+	sizeDesc           *prometheus.Desc
+	logBlockSizeDesc   *prometheus.Desc
+	physBlockSizeDesc  *prometheus.Desc
+	rotSpeedDesc       *prometheus.Desc
+
+	phyLinkRateDesc    *prometheus.Desc
+	phyMaxLinkRateDesc *prometheus.Desc
+	linkDegradedDesc   *prometheus.Desc
+
+	usageRemainingDesc *prometheus.Desc
+	powerOnHoursDesc   *prometheus.Desc
+	lifeRemainingDesc  *prometheus.Desc
+	wearoutDesc        *prometheus.Desc
+
+	locationInfoDesc     *prometheus.Desc
+	firmwareInfoDesc     *prometheus.Desc
+	firmwareAdvisoryDesc *prometheus.Desc
 }
 
-func NewSsacliPhysDiskCollector(diskID string, slotID string) *SsacliPhysDiskCollector {
-	return NewSsacliPhysDiskCollectorWithData(diskID, slotID, "")
+func NewSsacliPhysDiskCollector(diskID string, slotID string, opts Options) *SsacliPhysDiskCollector {
+	return NewSsacliPhysDiskCollectorWithData(diskID, slotID, "", 0, opts)
 }
 
-func NewSsacliPhysDiskCollectorWithData(diskID string, slotID string, data string) *SsacliPhysDiskCollector {
+// NewSsacliPhysDiskCollectorWithData creates a collector for pre-collected
+// "pd all show detail" output. controllerLinkRate is the highest link rate
+// the controller supports (see ControllerLinkRate), 0 if unknown.
+func NewSsacliPhysDiskCollectorWithData(diskID string, slotID string, data string, controllerLinkRate float64, opts Options) *SsacliPhysDiskCollector {
 	var (
-		namespace = "ssacli"
-		subsystem = "phys_disk"
-		labels    = []string{
+		namespace    = "ssacli"
+		subsystem    = "phys_disk"
+		legacyLabels = []string{
 			"physDiskID",
 			"physDiskDriveType",
 			"physDiskInterfaceType",
@@ -39,24 +70,178 @@ func NewSsacliPhysDiskCollectorWithData(diskID string, slotID string, data strin
 			"physDiskBay",
 			"physDiskSlotID",
 		}
+		idLabels   = physDiskLabels(opts)
+		infoLabels = append(idLabels,
+			"physDiskDriveType",
+			"physDiskInterfaceType",
+			"physDiskSerialNumber",
+			"physDiskModel",
+			"physDiskBay",
+			"role",
+		)
 	)
 
+	statusLabels := idLabels
+	if opts.Legacy {
+		statusLabels = legacyLabels
+	}
+
 	return &SsacliPhysDiskCollector{
-		diskID:  diskID,
-		slotID:  slotID,
-		rawData: data,
+		diskID:             diskID,
+		slotID:             slotID,
+		rawData:            data,
+		controllerLinkRate: controllerLinkRate,
+		opts:               opts,
 		physDiskStatusDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, subsystem, "status"),
 			"Hardware raid physical disk status (1 if OK, 0 otherwise)",
-			labels,
+			statusLabels,
+			nil,
+		),
+		physDiskStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "state"),
+			"Hardware raid physical disk state, 1 for the current state and 0 for all others",
+			append(idLabels, "state"),
+			nil,
+		),
+		physDiskInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "info"),
+			"Hardware raid physical disk identity, always 1",
+			infoLabels,
+			nil,
+		),
+		temperatureDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "temperature_celsius"),
+			"Hardware raid physical disk current temperature",
+			idLabels,
+			nil,
+		),
+		maxTemperatureDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "temperature_max_celsius"),
+			"Hardware raid physical disk highest temperature recorded by the drive",
+			idLabels,
+			nil,
+		),
+		tempThresholdDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "temperature_threshold_celsius"),
+			"Configured warning and critical temperature for the physical disk model",
+			append(idLabels, "level"),
+			nil,
+		),
+		sizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "size_bytes"),
+			"Hardware raid physical disk capacity",
+			idLabels,
+			nil,
+		),
+		logBlockSizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "logical_block_size_bytes"),
+			"Hardware raid physical disk logical block size",
+			idLabels,
+			nil,
+		),
+		physBlockSizeDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "physical_block_size_bytes"),
+			"Hardware raid physical disk physical block size",
+			idLabels,
+			nil,
+		),
+		rotSpeedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "rotational_speed_rpm"),
+			"Hardware raid physical disk rotational speed, 0 for solid state drives",
+			idLabels,
+			nil,
+		),
+		phyLinkRateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "phy_link_rate_gbps"),
+			"Hardware raid physical disk negotiated link rate per PHY, only for PHYs with a link",
+			append(idLabels, "phy"),
+			nil,
+		),
+		phyMaxLinkRateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "phy_max_link_rate_gbps"),
+			"Hardware raid physical disk maximum supported link rate per PHY",
+			append(idLabels, "phy"),
+			nil,
+		),
+		linkDegradedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "link_degraded"),
+			"1 if the physical disk negotiated a slower link than both the drive and its controller support",
+			idLabels,
+			nil,
+		),
+		usageRemainingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "ssd_usage_remaining_ratio"),
+			"Hardware raid solid state disk endurance left (0-1)",
+			idLabels,
+			nil,
+		),
+		powerOnHoursDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "power_on_hours"),
+			"Hardware raid physical disk power on hours as reported by the controller",
+			idLabels,
+			nil,
+		),
+		lifeRemainingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "estimated_life_remaining_days"),
+			"Hardware raid solid state disk estimated life left based on workload to date",
+			idLabels,
+			nil,
+		),
+		wearoutDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "ssd_wearout_tripped"),
+			"1 if the solid state disk tripped its SMART wearout threshold",
+			idLabels,
+			nil,
+		),
+		locationInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "location_info"),
+			"Where the physical disk sits, always 1",
+			append(idLabels, "serial_number", "location"),
+			nil,
+		),
+		firmwareInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "firmware_info"),
+			"Hardware raid physical disk model and firmware revision, always 1",
+			append(idLabels, "model", "firmware"),
+			nil,
+		),
+		firmwareAdvisoryDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "firmware_advisory"),
+			"Firmware advisories from the configured database affecting the physical disk, always 1",
+			append(idLabels, "advisory", "severity"),
 			nil,
 		),
 	}
 }
 
 func (c *SsacliPhysDiskCollector) Describe(ch chan<- *prometheus.Desc) {
-	if c.physDiskStatusDesc != nil {
-		ch <- c.physDiskStatusDesc
+	ds := []*prometheus.Desc{
+		c.physDiskStatusDesc,
+		c.physDiskStateDesc,
+		c.physDiskInfoDesc,
+		c.temperatureDesc,
+		c.maxTemperatureDesc,
+		c.tempThresholdDesc,
+		c.sizeDesc,
+		c.logBlockSizeDesc,
+		c.physBlockSizeDesc,
+		c.rotSpeedDesc,
+		c.phyLinkRateDesc,
+		c.phyMaxLinkRateDesc,
+		c.linkDegradedDesc,
+		c.usageRemainingDesc,
+		c.powerOnHoursDesc,
+		c.lifeRemainingDesc,
+		c.wearoutDesc,
+		c.locationInfoDesc,
+		c.firmwareInfoDesc,
+		c.firmwareAdvisoryDesc,
+	}
+	for _, d := range ds {
+		if d != nil {
+			ch <- d
+		}
 	}
 }
 
@@ -81,7 +266,7 @@ func (c *SsacliPhysDiskCollector) collect(ch chan<- prometheus.Metric) (*prometh
 		output = c.rawData
 	} else {
 		slotArg := "slot=" + c.slotID
-		out, err := exec.Command("ssacli", "ctrl", slotArg, "pd", c.diskID, "show", "detail").CombinedOutput()
+		out, err := RunSsacli("ctrl", slotArg, "pd", c.diskID, "show", "detail")
 		if err != nil {
 			return nil, err
 		}
@@ -94,22 +279,28 @@ func (c *SsacliPhysDiskCollector) collect(ch chan<- prometheus.Metric) (*prometh
 	}
 
 	for i := range data.SsacliPhysDiskData {
-		labels := []string{
-			c.diskID,
-			data.SsacliPhysDiskData[i].DriveType,
-			data.SsacliPhysDiskData[i].IntType,
-			data.SsacliPhysDiskData[i].Size,
-			data.SsacliPhysDiskData[i].Status,
-			data.SsacliPhysDiskData[i].SN,
-			data.SsacliPhysDiskData[i].Model,
-			fmt.Sprintf("%.0f", data.SsacliPhysDiskData[i].CurTemp),
-			fmt.Sprintf("%.0f", data.SsacliPhysDiskData[i].MaxTemp),
-			data.SsacliPhysDiskData[i].Bay,
-			c.slotID,
+		disk := data.SsacliPhysDiskData[i]
+		idLabels := physDiskLabelValues(c.opts, c.diskID, c.slotID, disk.Array)
+
+		statusLabels := idLabels
+		if c.opts.Legacy {
+			statusLabels = []string{
+				c.diskID,
+				disk.DriveType,
+				disk.IntType,
+				disk.Size,
+				disk.Status,
+				disk.SN,
+				disk.Model,
+				legacyTemperature(disk.CurTemp),
+				legacyTemperature(disk.MaxTemp),
+				disk.Bay,
+				c.slotID,
+			}
 		}
 
 		val := 0.0
-		if data.SsacliPhysDiskData[i].Status == "OK" {
+		if disk.Status == "OK" {
 			val = 1.0
 		}
 
@@ -117,9 +308,156 @@ func (c *SsacliPhysDiskCollector) collect(ch chan<- prometheus.Metric) (*prometh
 			c.physDiskStatusDesc,
 			prometheus.GaugeValue,
 			val,
-			labels...,
+			statusLabels...,
+		)
+
+		physDiskStates.collect(ch, c.physDiskStateDesc, disk.Status, idLabels...)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.physDiskInfoDesc,
+			prometheus.GaugeValue,
+			1,
+			append(idLabels,
+				disk.DriveType,
+				disk.IntType,
+				disk.SN,
+				disk.Model,
+				disk.Bay,
+				disk.Role(),
+			)...,
 		)
+		if t, ok := c.opts.TemperatureThresholds.lookup(disk.Model); ok {
+			if t.Warning != 0 {
+				ch <- prometheus.MustNewConstMetric(c.tempThresholdDesc, prometheus.GaugeValue, t.Warning, append(idLabels, "warning")...)
+			}
+			if t.Critical != 0 {
+				ch <- prometheus.MustNewConstMetric(c.tempThresholdDesc, prometheus.GaugeValue, t.Critical, append(idLabels, "critical")...)
+			}
+		}
+
+		sendMetric := func(desc *prometheus.Desc, val *float64) {
+			if val != nil {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *val, idLabels...)
+			}
+		}
+
+		sendMetric(c.temperatureDesc, disk.CurTemp)
+		sendMetric(c.maxTemperatureDesc, disk.MaxTemp)
+		sendMetric(c.sizeDesc, disk.SizeBytes)
+		sendMetric(c.logBlockSizeDesc, disk.LogicalBlockSize)
+		sendMetric(c.physBlockSizeDesc, disk.PhysicalBlockSize)
+		sendMetric(c.rotSpeedDesc, disk.RotationalSpeed)
+		sendMetric(c.usageRemainingDesc, disk.UsageRemaining)
+		sendMetric(c.powerOnHoursDesc, disk.PowerOnHours)
+		sendMetric(c.lifeRemainingDesc, disk.EstimatedLifeRemaining)
+		sendMetric(c.wearoutDesc, disk.SmartTripWearout)
+
+		ch <- prometheus.MustNewConstMetric(c.locationInfoDesc, prometheus.GaugeValue, 1, append(idLabels, disk.SN, locationText(c.slotID, c.diskID))...)
+
+		model := strings.Join(strings.Fields(disk.Model), " ")
+		if disk.Firmware != "" {
+			ch <- prometheus.MustNewConstMetric(c.firmwareInfoDesc, prometheus.GaugeValue, 1, append(idLabels, model, disk.Firmware)...)
+		}
+		for _, adv := range c.opts.FirmwareAdvisories.affecting(model, disk.Firmware) {
+			ch <- prometheus.MustNewConstMetric(c.firmwareAdvisoryDesc, prometheus.GaugeValue, 1, append(idLabels, adv.ID, adv.Severity)...)
+		}
+
+		for phy, rate := range disk.PHYTransferRates {
+			if rate > 0 {
+				ch <- prometheus.MustNewConstMetric(c.phyLinkRateDesc, prometheus.GaugeValue, rate, append(idLabels, strconv.Itoa(phy))...)
+			}
+		}
+		for phy, rate := range disk.PHYMaximumLinkRates {
+			if rate > 0 {
+				ch <- prometheus.MustNewConstMetric(c.phyMaxLinkRateDesc, prometheus.GaugeValue, rate, append(idLabels, strconv.Itoa(phy))...)
+			}
+		}
+		if degraded, ok := linkDegraded(disk, c.controllerLinkRate); ok {
+			val := 0.0
+			if degraded {
+				val = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(c.linkDegradedDesc, prometheus.GaugeValue, val, idLabels...)
+		}
 	}
 
 	return nil, nil
 }
+
+// linkDegraded reports whether the drive negotiated a slower link than both
+// the drive and its controller support. The rates other drives negotiated
+// are deliberately not used: a bad backplane slows down every drive behind
+// it. The second result is false when ssacli does not report enough to
+// tell.
+func linkDegraded(disk parser.SsacliPhysDiskData, controllerLinkRate float64) (bool, bool) {
+	negotiated := maxLinkRate(disk.PHYTransferRates)
+	expected := maxLinkRate(disk.PHYMaximumLinkRates)
+	if negotiated == 0 || expected == 0 {
+		return false, false
+	}
+
+	if controllerLinkRate > 0 && controllerLinkRate < expected {
+		expected = controllerLinkRate
+	}
+	return negotiated < expected, true
+}
+
+func maxLinkRate(rates []float64) float64 {
+	highest := 0.0
+	for _, r := range rates {
+		if r > highest {
+			highest = r
+		}
+	}
+	return highest
+}
+
+// physDiskLabels are the labels identifying a physical drive on the
+// ssacli_phys_disk_* metrics. The legacy shape only has the v1 ID labels.
+func physDiskLabels(opts Options) []string {
+	if opts.Legacy {
+		return []string{"physDiskID", "physDiskSlotID"}
+	}
+	return []string{"physDiskID", "physDiskSlotID", "array", "port", "port_type", "box", "bay"}
+}
+
+// physDiskLabelValues returns the values of physDiskLabels for a drive.
+func physDiskLabelValues(opts Options, diskID string, slotID string, array string) []string {
+	if opts.Legacy {
+		return []string{diskID, slotID}
+	}
+	return slices.Clip(append([]string{diskID, slotID, array}, locationLabels(diskID)...))
+}
+
+// locationLabels returns the port, port type, box and bay encoded in a
+// physical drive ID such as "1I:1:3", empty when the ID does not follow
+// that format.
+func locationLabels(diskID string) []string {
+	port, box, bay, ok := smartarray.ParseDriveLocation(diskID)
+	if !ok {
+		return []string{"", "", "", ""}
+	}
+	return []string{port, smartarray.PortType(port), strconv.Itoa(box), strconv.Itoa(bay)}
+}
+
+// legacyTemperature formats a temperature for the legacy status labels,
+// which showed 0 for drives without one.
+func legacyTemperature(t *float64) string {
+	if t == nil {
+		return "0"
+	}
+	return fmt.Sprintf("%.0f", *t)
+}
+
+// locationText describes where a drive sits for whoever has to go and
+// replace it, e.g. "Slot 0, Port 1I (internal), Box 1, Bay 3".
+func locationText(slotID string, diskID string) string {
+	port, box, bay, ok := smartarray.ParseDriveLocation(diskID)
+	if !ok {
+		return fmt.Sprintf("Slot %s, Drive %s", slotID, diskID)
+	}
+	if t := smartarray.PortType(port); t != "" {
+		port += " (" + t + ")"
+	}
+	return fmt.Sprintf("Slot %s, Port %s, Box %d, Bay %d", slotID, port, box, bay)
+}
@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestSsacliPhysDiskLegacyLabels(t *testing.T) {
+	data := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      physicaldrive 1I:1:1
+         Port: 1I
+         Box: 1
+         Bay: 1
+         Status: OK
+         Drive Type: Data Drive
+         Interface Type: SAS
+         Size: 1.2 TB
+         Serial Number: S3Z1NX0K123456
+         Model: HP      EG1200JEHMC
+         Current Temperature (C): 31
+         Maximum Temperature (C): 42
+         PHY Count: 2
+         PHY Transfer Rate: 12.0Gbps, Unknown
+         PHY Maximum Link Rate: 12.0Gbps, 12.0Gbps
+`
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(NewSsacliPhysDiskCollectorWithData("1I:1:1", "0", data, 12, Options{Legacy: true}))
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+
+	// The status keeps exactly the labels it had before v2
+	want := map[string][]string{
+		"ssacli_phys_disk_status": {
+			"physDiskBay", "physDiskCurrentTemperature", "physDiskDriveType",
+			"physDiskID", "physDiskInterfaceType", "physDiskMaximumTemperature",
+			"physDiskModel", "physDiskSerialNumber", "physDiskSize",
+			"physDiskSlotID", "physDiskStatus",
+		},
+	}
+
+	seen := 0
+	for _, mf := range families {
+		if !strings.HasPrefix(mf.GetName(), "ssacli_phys_disk_") {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			var names []string
+			for _, lp := range m.GetLabel() {
+				names = append(names, lp.GetName())
+			}
+
+			if w, ok := want[mf.GetName()]; ok {
+				if !slices.Equal(names, w) {
+					t.Errorf("%s: expected labels %v, got %v", mf.GetName(), w, names)
+				}
+				seen++
+				continue
+			}
+			for _, n := range []string{"array", "port", "port_type", "box", "bay"} {
+				if slices.Contains(names, n) {
+					t.Errorf("%s: unexpected label %q in legacy mode", mf.GetName(), n)
+				}
+			}
+			if !slices.Contains(names, "physDiskID") || !slices.Contains(names, "physDiskSlotID") {
+				t.Errorf("%s: missing ID labels, got %v", mf.GetName(), names)
+			}
+		}
+	}
+	if seen != 1 {
+		t.Errorf("expected one legacy status series, got %d", seen)
+	}
+}
@@ -2,7 +2,6 @@ package collector
 
 import (
 	"log"
-	"os/exec"
 	"strings"
 
 	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
@@ -16,21 +15,51 @@ var _ prometheus.Collector = &SsacliSumCollector{}
 
 // SsacliSumCollector Contain raid controller detail information
 type SsacliSumCollector struct {
+	rawData string
+	opts    Options
+
 	hwConSlotDesc      *prometheus.Desc
 	cacheSizeDesc      *prometheus.Desc
 	availCacheSizeDesc *prometheus.Desc
 	hwConTempDesc      *prometheus.Desc
 	cahceModuTempDesc  *prometheus.Desc
 	batteryTempDesc    *prometheus.Desc
+
+	stateDesc      *prometheus.Desc
+	cacheStateDesc *prometheus.Desc
+	infoDesc       *prometheus.Desc
+
+	cacheRatioDesc          *prometheus.Desc
+	cacheBoardPresentDesc   *prometheus.Desc
+	noBatteryWriteCacheDesc *prometheus.Desc
+	driveWriteCacheDesc     *prometheus.Desc
+	cacheBackupPowerDesc    *prometheus.Desc
+
+	settingsInfoDesc             *prometheus.Desc
+	spareActivationDesc          *prometheus.Desc
+	smartPathDesc                *prometheus.Desc
+	surfaceScanDelayDesc         *prometheus.Desc
+	parallelScanSupportedDesc    *prometheus.Desc
+	parallelScanCountDesc        *prometheus.Desc
+	parallelScanMaxCountDesc     *prometheus.Desc
+	queueDepthDesc               *prometheus.Desc
+	elevatorSortDesc             *prometheus.Desc
+	degradedPerfOptimizationDesc *prometheus.Desc
 }
 
 // NewSsacliSumCollector Create new collector
-func NewSsacliSumCollector() *SsacliSumCollector {
+func NewSsacliSumCollector(opts Options) *SsacliSumCollector {
+	return NewSsacliSumCollectorWithData("", opts)
+}
+
+// NewSsacliSumCollectorWithData Create new collector from pre-collected
+// "ctrl all show detail" output
+func NewSsacliSumCollectorWithData(data string, opts Options) *SsacliSumCollector {
 	// Init labels
 	var (
-		namespace = "ssacli"
-		subsystem = "hw_raid_controller"
-		labels    = []string{
+		namespace    = "ssacli"
+		subsystem    = "hw_raid_controller"
+		legacyLabels = []string{
 			"raidControllerSN",
 			"raidControllerStatus",
 			"raidControllerFirmVersion",
@@ -39,10 +68,30 @@ func NewSsacliSumCollector() *SsacliSumCollector {
 			"raidControllerDriverName",
 			"raidControllerDriverVersion",
 		}
+		stateLabels = []string{
+			"slot",
+			"state",
+		}
+		infoLabels = []string{
+			"slot",
+			"raidControllerSN",
+			"raidControllerFirmVersion",
+			"raidControllerEncryption",
+			"raidControllerDriverName",
+			"raidControllerDriverVersion",
+		}
 	)
+
+	labels := []string{"slot"}
+	if opts.Legacy {
+		labels = legacyLabels
+	}
+
 	// Rerutn Colected metric to ch <-
 	// Include labels
 	return &SsacliSumCollector{
+		rawData: data,
+		opts:    opts,
 		hwConSlotDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, subsystem, "slot"),
 			"Hardware raid controller slot usage",
@@ -75,10 +124,126 @@ func NewSsacliSumCollector() *SsacliSumCollector {
 		),
 		batteryTempDesc: prometheus.NewDesc(
 			prometheus.BuildFQName(namespace, subsystem, "temperature_battery"),
-			"Hardware raid controller battery/capacitor module temperature",
+			"Hardware raid controller battery/capacitor module temperature. Deprecated, use ssacli_controller_battery_temperature_celsius",
 			labels,
 			nil,
 		),
+		stateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "state"),
+			"Hardware raid controller state, 1 for the current state and 0 for all others",
+			stateLabels,
+			nil,
+		),
+		cacheStateDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "cache_state"),
+			"Hardware raid controller cache state, 1 for the current state and 0 for all others",
+			stateLabels,
+			nil,
+		),
+		infoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "info"),
+			"Hardware raid controller identity, always 1",
+			infoLabels,
+			nil,
+		),
+		cacheRatioDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "cache_ratio"),
+			"Share of the controller cache assigned to read or write caching (0-1)",
+			[]string{"slot", "operation"},
+			nil,
+		),
+		cacheBoardPresentDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "cache_board_present"),
+			"1 if the controller has a cache module installed",
+			[]string{"slot"},
+			nil,
+		),
+		noBatteryWriteCacheDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "no_battery_write_cache_enabled"),
+			"1 if write caching stays enabled without a working battery/capacitor",
+			[]string{"slot"},
+			nil,
+		),
+		driveWriteCacheDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "drive_write_cache_state"),
+			"Physical drive write cache policy, 1 for the current state and 0 for all others",
+			stateLabels,
+			nil,
+		),
+		cacheBackupPowerDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "cache_backup_power_source"),
+			"Cache backup power source, 1 for the current source and 0 for all others",
+			[]string{"slot", "source"},
+			nil,
+		),
+		settingsInfoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "settings_info"),
+			"Hardware raid controller operational settings, always 1",
+			[]string{
+				"slot",
+				"rebuild_priority",
+				"expand_priority",
+				"surface_scan_mode",
+				"elevator_sort",
+				"degraded_performance_optimization",
+				"controller_mode",
+			},
+			nil,
+		),
+		spareActivationDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "spare_activation_mode"),
+			"When spare drives take over, 1 for the current mode and 0 for all others",
+			[]string{"slot", "mode"},
+			nil,
+		),
+		smartPathDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "smart_path_state"),
+			"HPE SSD Smart Path state, 1 for the current state and 0 for all others",
+			stateLabels,
+			nil,
+		),
+		surfaceScanDelayDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "surface_scan_delay_seconds"),
+			"Idle time before the controller starts a surface scan",
+			[]string{"slot"},
+			nil,
+		),
+		parallelScanSupportedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "parallel_surface_scan_supported"),
+			"1 if the controller can surface scan several drives at once",
+			[]string{"slot"},
+			nil,
+		),
+		parallelScanCountDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "parallel_surface_scan_count"),
+			"Number of drives the controller surface scans at once",
+			[]string{"slot"},
+			nil,
+		),
+		parallelScanMaxCountDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "parallel_surface_scan_max_count"),
+			"Maximum number of drives the controller can surface scan at once",
+			[]string{"slot"},
+			nil,
+		),
+		queueDepthDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "queue_depth"),
+			"Hardware raid controller queue depth, only when not set to Automatic",
+			[]string{"slot"},
+			nil,
+		),
+		elevatorSortDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "elevator_sort_enabled"),
+			"1 if elevator sort is enabled",
+			[]string{"slot"},
+			nil,
+		),
+		degradedPerfOptimizationDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "controller", "degraded_performance_optimization_enabled"),
+			"1 if degraded performance optimization is enabled",
+			[]string{"slot"},
+			nil,
+		),
 	}
 }
 
@@ -91,6 +256,24 @@ func (c *SsacliSumCollector) Describe(ch chan<- *prometheus.Desc) {
 		c.hwConTempDesc,
 		c.cahceModuTempDesc,
 		c.batteryTempDesc,
+		c.stateDesc,
+		c.cacheStateDesc,
+		c.infoDesc,
+		c.cacheRatioDesc,
+		c.cacheBoardPresentDesc,
+		c.noBatteryWriteCacheDesc,
+		c.driveWriteCacheDesc,
+		c.cacheBackupPowerDesc,
+		c.settingsInfoDesc,
+		c.spareActivationDesc,
+		c.smartPathDesc,
+		c.surfaceScanDelayDesc,
+		c.parallelScanSupportedDesc,
+		c.parallelScanCountDesc,
+		c.parallelScanMaxCountDesc,
+		c.queueDepthDesc,
+		c.elevatorSortDesc,
+		c.degradedPerfOptimizationDesc,
 	}
 	for _, d := range ds {
 		ch <- d
@@ -109,11 +292,15 @@ func (c *SsacliSumCollector) Collect(ch chan<- prometheus.Metric) {
 }
 
 func (c *SsacliSumCollector) collect(ch chan<- prometheus.Metric) (*prometheus.Desc, error) {
-	out, err := exec.Command("ssacli", "ctrl", "all", "show", "detail").CombinedOutput()
+	out := []byte(c.rawData)
+	if c.rawData == "" {
+		var err error
+		out, err = RunSsacli("ctrl", "all", "show", "detail")
 
-	if err != nil {
-		//log.Debugln("[ERROR] ssacli log: \n%s\n", out)
-		return nil, err
+		if err != nil {
+			//log.Debugln("[ERROR] ssacli log: \n%s\n", out)
+			return nil, err
+		}
 	}
 
 	// Remove extra spaces and empty lines at the edges
@@ -124,10 +311,18 @@ func (c *SsacliSumCollector) collect(ch chan<- prometheus.Metric) (*prometheus.D
 		log.Printf("[FATAL] Unable get data from ssacli summary exporter")
 		return nil, nil
 	}
+	if data.Err != nil {
+		log.Printf("[ERROR] failed parsing controller detail: %v", data.Err)
+	}
 
 	for i := range data.SsacliSumData {
 
 		var (
+			slot   = data.SsacliSumData[i].SlotID
+			labels = []string{slot}
+		)
+
+		if c.opts.Legacy {
 			labels = []string{
 				data.SsacliSumData[i].SerialNumber,
 				data.SsacliSumData[i].ContStatus,
@@ -137,7 +332,7 @@ func (c *SsacliSumCollector) collect(ch chan<- prometheus.Metric) (*prometheus.D
 				data.SsacliSumData[i].DriverName,
 				data.SsacliSumData[i].DriverVersion,
 			}
-		)
+		}
 
 		ConID = data.SsacliSumData[i].SlotID
 
@@ -147,37 +342,83 @@ func (c *SsacliSumCollector) collect(ch chan<- prometheus.Metric) (*prometheus.D
 			float64(data.SsacliSumData[i].Slot),
 			labels...,
 		)
+		sendOptional := func(desc *prometheus.Desc, val *float64) {
+			if val != nil {
+				ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *val, labels...)
+			}
+		}
+		sendOptional(c.cacheSizeDesc, data.SsacliSumData[i].TotalCacheSize)
+		sendOptional(c.availCacheSizeDesc, data.SsacliSumData[i].AvailCacheSize)
+		sendOptional(c.hwConTempDesc, data.SsacliSumData[i].ContTemp)
+		sendOptional(c.cahceModuTempDesc, data.SsacliSumData[i].CahceModuTemp)
+		sendOptional(c.batteryTempDesc, data.SsacliSumData[i].BatteryTemp)
+
 		ch <- prometheus.MustNewConstMetric(
-			c.cacheSizeDesc,
-			prometheus.GaugeValue,
-			float64(data.SsacliSumData[i].TotalCacheSize),
-			labels...,
-		)
-		ch <- prometheus.MustNewConstMetric(
-			c.availCacheSizeDesc,
-			prometheus.GaugeValue,
-			float64(data.SsacliSumData[i].AvailCacheSize),
-			labels...,
-		)
-		ch <- prometheus.MustNewConstMetric(
-			c.hwConTempDesc,
-			prometheus.GaugeValue,
-			float64(data.SsacliSumData[i].ContTemp),
-			labels...,
-		)
-		ch <- prometheus.MustNewConstMetric(
-			c.cahceModuTempDesc,
-			prometheus.GaugeValue,
-			float64(data.SsacliSumData[i].CahceModuTemp),
-			labels...,
-		)
-		ch <- prometheus.MustNewConstMetric(
-			c.batteryTempDesc,
+			c.infoDesc,
 			prometheus.GaugeValue,
-			float64(data.SsacliSumData[i].BatteryTemp),
-			labels...,
+			1,
+			slot,
+			data.SsacliSumData[i].SerialNumber,
+			data.SsacliSumData[i].FirmVersion,
+			data.SsacliSumData[i].Encryption,
+			data.SsacliSumData[i].DriverName,
+			data.SsacliSumData[i].DriverVersion,
 		)
 
+		controllerStates.collect(ch, c.stateDesc, data.SsacliSumData[i].ContStatus, slot)
+		cacheStates.collect(ch, c.cacheStateDesc, data.SsacliSumData[i].CacheStatus, slot)
+		driveWriteCacheStates.collect(ch, c.driveWriteCacheDesc, data.SsacliSumData[i].DriveWriteCache, slot)
+		cacheBackupPowerSources.collect(ch, c.cacheBackupPowerDesc, data.SsacliSumData[i].CacheBackupPowerSource, slot)
+
+		if r := data.SsacliSumData[i].CacheReadRatio; r != nil {
+			ch <- prometheus.MustNewConstMetric(c.cacheRatioDesc, prometheus.GaugeValue, *r, slot, "read")
+		}
+		if r := data.SsacliSumData[i].CacheWriteRatio; r != nil {
+			ch <- prometheus.MustNewConstMetric(c.cacheRatioDesc, prometheus.GaugeValue, *r, slot, "write")
+		}
+		if v := data.SsacliSumData[i].CacheBoardPresent; v != nil {
+			ch <- prometheus.MustNewConstMetric(c.cacheBoardPresentDesc, prometheus.GaugeValue, *v, slot)
+		}
+		if v := data.SsacliSumData[i].NoBatteryWriteCache; v != nil {
+			ch <- prometheus.MustNewConstMetric(c.noBatteryWriteCacheDesc, prometheus.GaugeValue, *v, slot)
+		}
+
+		c.collectSettings(ch, data.SsacliSumData[i])
+
 	}
 	return nil, nil
 }
+
+func (c *SsacliSumCollector) collectSettings(ch chan<- prometheus.Metric, ctrl parser.SsacliSumData) {
+	slot := ctrl.SlotID
+
+	ch <- prometheus.MustNewConstMetric(
+		c.settingsInfoDesc,
+		prometheus.GaugeValue,
+		1,
+		slot,
+		ctrl.RebuildPriority,
+		ctrl.ExpandPriority,
+		ctrl.SurfaceScanMode,
+		ctrl.ElevatorSort,
+		ctrl.DegradedPerformanceOptimization,
+		ctrl.ControllerMode,
+	)
+
+	spareActivationModes.collect(ch, c.spareActivationDesc, ctrl.SpareActivationMode, slot)
+	smartPathStates.collect(ch, c.smartPathDesc, ctrl.SmartPath, slot)
+
+	send := func(desc *prometheus.Desc, val *float64) {
+		if val != nil {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, *val, slot)
+		}
+	}
+
+	send(c.surfaceScanDelayDesc, ctrl.SurfaceScanDelay)
+	send(c.parallelScanSupportedDesc, ctrl.ParallelSurfaceScanSupported)
+	send(c.parallelScanCountDesc, ctrl.ParallelSurfaceScanCount)
+	send(c.parallelScanMaxCountDesc, ctrl.MaxParallelSurfaceScanCount)
+	send(c.queueDepthDesc, ctrl.QueueDepthCount)
+	send(c.elevatorSortDesc, ctrl.ElevatorSortEnabled)
+	send(c.degradedPerfOptimizationDesc, ctrl.DegradedPerformanceOptimizationEnabled)
+}
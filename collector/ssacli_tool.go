@@ -0,0 +1,172 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SsacliToolCollector{}
+
+// toolNames are the names the Smart Array CLI has shipped under, newest
+// first. Gen8 and older servers may only have one of the older ones.
+var toolNames = []string{"ssacli", "hpssacli", "hpacucli"}
+
+// Tool is the Smart Array CLI binary every collector runs
+type Tool struct {
+	// Path is what gets executed, Binary its base name.
+	Path   string
+	Binary string
+	// Version is reported by "<binary> version", empty when the tool
+	// does not print one.
+	Version string
+}
+
+// tool is set once at startup by SetTool and read by every collector.
+// Until then "ssacli" is looked up in PATH as before.
+var tool = &Tool{Path: "ssacli", Binary: "ssacli"}
+
+// DiscoverTool resolves the Smart Array CLI and detects its version. An
+// explicit path is used as is, otherwise ssacli, hpssacli and hpacucli are
+// looked up in PATH in that order.
+func DiscoverTool(path string) (*Tool, error) {
+	candidates := toolNames
+	if path != "" {
+		candidates = []string{path}
+	}
+
+	for _, name := range candidates {
+		p, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		t := &Tool{Path: p, Binary: filepath.Base(p)}
+		// Some releases exit non-zero after printing the version, so
+		// the output decides
+		ctx, cancel := runContext()
+		out, _ := t.run(ctx, "version")
+		cancel()
+		t.Version = parser.ParseSsacliVersion(string(out))
+		return t, nil
+	}
+
+	if path != "" {
+		return nil, fmt.Errorf("%s not found", path)
+	}
+	return nil, fmt.Errorf("none of %s found in PATH", strings.Join(toolNames, ", "))
+}
+
+// SetTool makes t the CLI run by the collectors. It is meant to be called
+// before the exporter is registered.
+func SetTool(t *Tool) {
+	tool = t
+}
+
+// CurrentTool returns the CLI run by the collectors.
+func CurrentTool() *Tool {
+	return tool
+}
+
+// CommandContext returns the command running the tool with args, killed
+// when ctx is done
+func (t *Tool) CommandContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, t.Path, args...)
+	// Do not wait for children of a killed run that keep its output open
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// runTimeout bounds every run including the wait for the lock, 0 disables
+// it. It is set once at startup by SetRunTimeout.
+var runTimeout = 30 * time.Second
+
+// SetRunTimeout limits how long a single ssacli run, including the wait for
+// other runs, may take before it is killed. 0 disables the limit. It is
+// meant to be called before the exporter is registered.
+func SetRunTimeout(d time.Duration) {
+	runTimeout = d
+}
+
+func runContext() (context.Context, context.CancelFunc) {
+	if runTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), runTimeout)
+}
+
+// RunSsacli runs the configured Smart Array CLI with args and returns its
+// combined output. Runs are serialized, see SetLockFile, and time out, see
+// SetRunTimeout.
+func RunSsacli(args ...string) ([]byte, error) {
+	ctx, cancel := runContext()
+	defer cancel()
+	return tool.run(ctx, args...)
+}
+
+func (t *Tool) run(ctx context.Context, args ...string) ([]byte, error) {
+	release, err := ssacliRuns.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return t.CommandContext(ctx, args...).CombinedOutput()
+}
+
+// SsacliToolCollector exports which Smart Array CLI the exporter runs and
+// how long runs wait for each other
+type SsacliToolCollector struct {
+	infoDesc        *prometheus.Desc
+	lockWaitDesc    *prometheus.Desc
+	lockWaitingDesc *prometheus.Desc
+}
+
+// NewSsacliToolCollector Create new collector
+func NewSsacliToolCollector() *SsacliToolCollector {
+	return &SsacliToolCollector{
+		infoDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("ssacli", "tool", "info"),
+			"Smart Array CLI binary run by the exporter and its version",
+			[]string{"binary", "version"},
+			nil,
+		),
+		lockWaitDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("ssacli", "tool", "lock_wait_seconds"),
+			"Time Smart Array CLI runs waited for the previous run to finish",
+			nil,
+			nil,
+		),
+		lockWaitingDesc: prometheus.NewDesc(
+			prometheus.BuildFQName("ssacli", "tool", "lock_waiting"),
+			"Smart Array CLI runs currently waiting for the lock",
+			nil,
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SsacliToolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.infoDesc
+	ch <- c.lockWaitDesc
+	ch <- c.lockWaitingDesc
+}
+
+// Collect create collector
+func (c *SsacliToolCollector) Collect(ch chan<- prometheus.Metric) {
+	t := CurrentTool()
+	version := t.Version
+	if version == "" {
+		version = "unknown"
+	}
+	ch <- prometheus.MustNewConstMetric(c.infoDesc, prometheus.GaugeValue, 1, t.Binary, version)
+
+	s := ssacliRuns.stats()
+	ch <- prometheus.MustNewConstHistogram(c.lockWaitDesc, s.count, s.sum, s.buckets)
+	ch <- prometheus.MustNewConstMetric(c.lockWaitingDesc, prometheus.GaugeValue, float64(s.waiting))
+}
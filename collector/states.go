@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unknownState is reported for statuses missing from a stateSet, so new
+// firmware strings still show up as a series that can be alerted on.
+const unknownState = "unknown"
+
+// stateSet maps the free-text status ssacli prints for a component onto a
+// fixed list of state names, exported as one series per state. Statuses
+// are matched case-insensitively by prefix because ssacli appends details
+// to some of them, e.g. "Failed (Replace Batteries/Capacitors)". Keep the
+// table in README.md in sync when adding states.
+type stateSet []stateMapping
+
+type stateMapping struct {
+	state    string
+	statuses []string
+}
+
+var (
+	controllerStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"failed", []string{"Failed"}},
+		{"offline", []string{"Offline"}},
+	}
+
+	cacheStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"temporarily_disabled", []string{"Temporarily Disabled"}},
+		{"permanently_disabled", []string{"Permanently Disabled"}},
+		{"not_configured", []string{"Not Configured"}},
+		{"failed", []string{"Failed"}},
+	}
+
+	driveWriteCacheStates = stateSet{
+		{"enabled", []string{"Enabled"}},
+		{"disabled", []string{"Disabled"}},
+		{"unchanged", []string{"Unchanged"}},
+	}
+
+	cacheBackupPowerSources = stateSet{
+		{"batteries", []string{"Batteries", "Battery"}},
+		{"capacitors", []string{"Capacitors", "Capacitor"}},
+		{"none", []string{"None"}},
+	}
+
+	spareActivationModes = stateSet{
+		{"failure", []string{"Activate on physical drive failure"}},
+		{"predictive_failure", []string{"Activate on physical drive predictive failure"}},
+	}
+
+	batteryStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"charging", []string{"Charging", "Recharging"}},
+		{"not_fully_charged", []string{"Not Fully Charged"}},
+		{"failed", []string{"Failed"}},
+		{"not_present", []string{"Not Present"}},
+	}
+
+	batteryChargeStates = stateSet{
+		{"charged", []string{"Fully Charged", "Charged"}},
+		{"charging", []string{"Charging", "Recharging"}},
+		{"not_charged", []string{"Not Fully Charged", "Not Charged", "Discharged"}},
+	}
+
+	smartPathStates = stateSet{
+		{"enabled", []string{"Enable"}},
+		{"disabled", []string{"Disable"}},
+	}
+
+	accelerationMethods = stateSet{
+		{"controller_cache", []string{"Controller Cache"}},
+		{"smart_path", []string{"HPE SSD Smart Path", "HP SSD Smart Path", "SSD Smart Path"}},
+		{"none", []string{"None", "All disabled"}},
+	}
+
+	enclosureStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"degraded", []string{"Degraded"}},
+		{"failed", []string{"Failed"}},
+		{"not_installed", []string{"Not Installed", "Not Present"}},
+	}
+
+	powerSupplyStates = stateSet{
+		{"redundant", []string{"Redundant"}},
+		{"not_redundant", []string{"Not Redundant"}},
+		{"ok", []string{"OK"}},
+		{"failed", []string{"Failed"}},
+	}
+
+	arrayStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"failed_physical_drive", []string{"Failed Physical Drive"}},
+		{"failed", []string{"Failed"}},
+	}
+
+	logDiskStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"failed", []string{"Failed"}},
+		{"interim_recovery", []string{"Interim Recovery Mode"}},
+		{"ready_for_rebuild", []string{"Ready for Rebuild"}},
+		{"recovering", []string{"Recovering", "Rebuilding"}},
+		{"wrong_drive_replaced", []string{"Wrong Physical Drive Replaced"}},
+		{"drive_not_connected", []string{"Physical Drive Not Properly Connected"}},
+		{"transforming", []string{"Transforming", "Moving"}},
+		{"expanding", []string{"Expanding", "Extending"}},
+		{"queued", []string{"Queued for Expansion", "Queued for Transformation"}},
+		{"not_yet_available", []string{"Not Yet Available"}},
+		{"overheating", []string{"Hardware Overheating", "Hardware Has Overheated"}},
+		{"erasing", []string{"Erase In Progress", "Erasing"}},
+		{"disabled", []string{"Disabled"}},
+	}
+
+	physDiskStates = stateSet{
+		{"ok", []string{"OK"}},
+		{"failed", []string{"Failed"}},
+		{"predictive_failure", []string{"Predictive Failure"}},
+		{"rebuilding", []string{"Rebuilding"}},
+		{"erasing", []string{"Erase In Progress", "Erasing"}},
+		{"erase_queued", []string{"Erase Queued"}},
+		{"erase_complete", []string{"Erase Complete"}},
+		{"unsupported", []string{"Unsupported"}},
+	}
+)
+
+// state returns the state name for a status printed by ssacli.
+func (s stateSet) state(status string) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	for _, m := range s {
+		for _, prefix := range m.statuses {
+			if strings.HasPrefix(status, strings.ToLower(prefix)) {
+				return m.state
+			}
+		}
+	}
+	return unknownState
+}
+
+// collect sends one series per state, 1 for the current one and 0 for the
+// others. Nothing is sent when ssacli did not report a status at all.
+func (s stateSet) collect(ch chan<- prometheus.Metric, desc *prometheus.Desc, status string, labels ...string) {
+	if status == "" {
+		return
+	}
+
+	current := s.state(status)
+	send := func(state string) {
+		val := 0.0
+		if state == current {
+			val = 1.0
+		}
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, val, append(labels, state)...)
+	}
+
+	for _, m := range s {
+		send(m.state)
+	}
+	send(unknownState)
+}
@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"strconv"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/pkg/smartarray"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = &SummaryCollector{}
+
+// Reasons a physical drive is recommended for replacement
+const (
+	reasonFailed             = "failed"
+	reasonPredictiveFailure  = "predictive_failure"
+	reasonSmartHealth        = "smart_health"
+	reasonSSDWearout         = "ssd_wearout"
+	reasonReallocatedSectors = "reallocated_sectors"
+)
+
+var replaceReasons = []string{
+	reasonFailed,
+	reasonPredictiveFailure,
+	reasonSmartHealth,
+	reasonSSDWearout,
+	reasonReallocatedSectors,
+}
+
+// SummaryCollector answers whether the host needs a technician visit and
+// for which drives, combining ssacli status, SSD wear and the smartctl
+// results of the same scrape
+type SummaryCollector struct {
+	controllers []*smartarray.Controller
+	results     *SmartResults
+	opts        Options
+
+	requiringReplacementDesc *prometheus.Desc
+	replaceRecommendedDesc   *prometheus.Desc
+}
+
+// NewSummaryCollector Create new collector. It must run after the smartctl
+// collectors that recorded their verdicts in results.
+func NewSummaryCollector(controllers []*smartarray.Controller, results *SmartResults, opts Options) *SummaryCollector {
+	namespace := "ssacli"
+
+	return &SummaryCollector{
+		controllers: controllers,
+		results:     results,
+		opts:        opts,
+		requiringReplacementDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "host", "drives_requiring_replacement"),
+			"Number of physical drives recommended for replacement for any reason",
+			nil,
+			nil,
+		),
+		replaceRecommendedDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "phys_disk", "replace_recommended"),
+			"1 if the physical disk should be replaced for the given reason",
+			append(physDiskLabels(opts), "reason"),
+			nil,
+		),
+	}
+}
+
+// Describe return all description to chanel
+func (c *SummaryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requiringReplacementDesc
+	ch <- c.replaceRecommendedDesc
+}
+
+// Collect create collector
+func (c *SummaryCollector) Collect(ch chan<- prometheus.Metric) {
+	requiring := 0
+
+	for _, ctrl := range c.controllers {
+		slot := strconv.Itoa(ctrl.Slot)
+
+		for _, pd := range ctrl.PhysicalDrives() {
+			array := ""
+			if pd.Array != nil {
+				array = pd.Array.ID
+			}
+			labels := physDiskLabelValues(c.opts, pd.ID, slot, array)
+
+			reasons := c.reasons(pd)
+			if len(reasons) > 0 {
+				requiring++
+			}
+
+			for _, reason := range replaceReasons {
+				val := 0.0
+				if reasons[reason] {
+					val = 1.0
+				}
+				ch <- prometheus.MustNewConstMetric(
+					c.replaceRecommendedDesc,
+					prometheus.GaugeValue,
+					val,
+					append(labels, reason)...,
+				)
+			}
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.requiringReplacementDesc, prometheus.GaugeValue, float64(requiring))
+}
+
+// reasons returns the reasons to replace a drive, empty for healthy ones.
+func (c *SummaryCollector) reasons(pd *smartarray.PhysicalDrive) map[string]bool {
+	reasons := make(map[string]bool)
+
+	switch physDiskStates.state(pd.Status) {
+	case "failed":
+		reasons[reasonFailed] = true
+	case "predictive_failure":
+		reasons[reasonPredictiveFailure] = true
+	}
+
+	if pd.Endurance != nil && pd.Endurance.WearoutTripped {
+		reasons[reasonSSDWearout] = true
+	}
+
+	if r, ok := c.results.get(pd.SerialNumber); ok {
+		if r.healthFailed {
+			reasons[reasonSmartHealth] = true
+		}
+		if c.opts.ReallocatedSectorsLimit > 0 && r.reallocated != nil && *r.reallocated > c.opts.ReallocatedSectorsLimit {
+			reasons[reasonReallocatedSectors] = true
+		}
+	}
+
+	return reasons
+}
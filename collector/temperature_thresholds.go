@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// TemperatureThresholds is the table of warning and critical temperatures
+// for physical drives, loaded from a JSON file:
+//
+//	{
+//	  "default": {"warning": 50, "critical": 60},
+//	  "models": [
+//	    {"model": "*MO0400*", "warning": 60, "critical": 70}
+//	  ]
+//	}
+//
+// Models are glob patterns (see path.Match) matched against the drive model
+// as printed by ssacli with runs of whitespace collapsed, e.g.
+// "HP EG0600FBVFP". The first matching entry wins, drives matching none use
+// the default.
+type TemperatureThresholds struct {
+	Default *TemperatureThreshold       `json:"default"`
+	Models  []ModelTemperatureThreshold `json:"models"`
+}
+
+// TemperatureThreshold holds the limits for one drive model. A zero value
+// leaves that level unset.
+type TemperatureThreshold struct {
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+}
+
+// ModelTemperatureThreshold is a TemperatureThreshold for the drive models
+// matching Model.
+type ModelTemperatureThreshold struct {
+	Model string `json:"model"`
+	TemperatureThreshold
+}
+
+// LoadTemperatureThresholds reads and validates a threshold table.
+func LoadTemperatureThresholds(file string) (*TemperatureThresholds, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var t TemperatureThresholds
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", file, err)
+	}
+
+	for _, m := range t.Models {
+		if _, err := path.Match(m.Model, ""); err != nil {
+			return nil, fmt.Errorf("invalid model pattern %q in %s: %w", m.Model, file, err)
+		}
+	}
+
+	return &t, nil
+}
+
+// lookup returns the thresholds that apply to a drive model.
+func (t *TemperatureThresholds) lookup(model string) (TemperatureThreshold, bool) {
+	if t == nil {
+		return TemperatureThreshold{}, false
+	}
+
+	model = strings.Join(strings.Fields(model), " ")
+	for _, m := range t.Models {
+		if ok, _ := path.Match(m.Model, model); ok {
+			return m.TemperatureThreshold, true
+		}
+	}
+
+	if t.Default != nil {
+		return *t.Default, true
+	}
+	return TemperatureThreshold{}, false
+}
@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestTemperatureThresholdsLookup(t *testing.T) {
+	file := writeTestFile(t, `{
+  "default": {"warning": 50, "critical": 60},
+  "models": [
+    {"model": "HP MO0400*", "warning": 65, "critical": 75},
+    {"model": "*MO0400*", "warning": 60, "critical": 70},
+    {"model": "*EG0600*", "critical": 55}
+  ]
+}`)
+
+	thresholds, err := LoadTemperatureThresholds(file)
+	if err != nil {
+		t.Fatalf("LoadTemperatureThresholds: %v", err)
+	}
+
+	tests := []struct {
+		model string
+		want  TemperatureThreshold
+	}{
+		// The first matching pattern wins over later, broader ones
+		{"HP MO0400JDVEU", TemperatureThreshold{65, 75}},
+		{"HPE MO0400JDVEU", TemperatureThreshold{60, 70}},
+		// Whitespace runs in the ssacli model are collapsed before matching
+		{"HP     EG0600FBVFP", TemperatureThreshold{0, 55}},
+		// No pattern matches, the default applies
+		{"ATA MK000480GWCEV", TemperatureThreshold{50, 60}},
+	}
+
+	for _, tt := range tests {
+		got, ok := thresholds.lookup(tt.model)
+		if !ok || got != tt.want {
+			t.Errorf("lookup(%q): expected %+v, got %+v (%v)", tt.model, tt.want, got, ok)
+		}
+	}
+}
+
+func TestTemperatureThresholdsNoMatch(t *testing.T) {
+	thresholds, err := LoadTemperatureThresholds(writeTestFile(t, `{"models": [{"model": "*MO0400*", "warning": 60}]}`))
+	if err != nil {
+		t.Fatalf("LoadTemperatureThresholds: %v", err)
+	}
+
+	if got, ok := thresholds.lookup("HP EG0600FBVFP"); ok {
+		t.Errorf("expected no thresholds without a default, got %+v", got)
+	}
+
+	var none *TemperatureThresholds
+	if _, ok := none.lookup("HP EG0600FBVFP"); ok {
+		t.Errorf("expected no thresholds when none are configured")
+	}
+}
+
+func TestLoadTemperatureThresholdsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"malformed JSON":  `{"default": {"warning": 50,}`,
+		"wrong type":      `{"default": {"warning": "hot"}}`,
+		"invalid pattern": `{"models": [{"model": "HP [MO", "warning": 60}]}`,
+	}
+
+	for name, content := range tests {
+		if _, err := LoadTemperatureThresholds(writeTestFile(t, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := LoadTemperatureThresholds(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("missing file: expected an error")
+	}
+}
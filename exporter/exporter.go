@@ -5,6 +5,8 @@ import (
 	"sync"
 
 	"github.com/CloudOpsKit/smartctl_ssacli_exporter/collector"
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/pkg/smartarray"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -16,6 +18,7 @@ import (
 // with Prometheus.
 type Exporter struct {
 	devicePath string
+	opts       collector.Options
 }
 
 var _ prometheus.Collector = &Exporter{}
@@ -23,24 +26,46 @@ var _ prometheus.Collector = &Exporter{}
 // New creates a new Exporter which collects metrics by creating a apcupsd
 // client using the input ClientFunc.
 func New(devicePath string) *Exporter {
+	return NewWithOptions(devicePath, collector.Options{})
+}
+
+// NewWithOptions creates a new Exporter whose collectors are configured
+// with opts.
+func NewWithOptions(devicePath string, opts collector.Options) *Exporter {
 	return &Exporter{
 		devicePath: devicePath,
+		opts:       opts,
 	}
 }
 
 // Describe sends all the descriptors of the collectors included to
 // the provided channel.
 func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
-	collector.NewSsacliSumCollector().Describe(ch)
-	collector.NewSsacliPhysDiskCollector("", "").Describe(ch)
-	collector.NewSmartctlDiskCollector(e.devicePath, "", 0).Describe(ch)
-	collector.NewSsacliLogDiskCollector("", "").Describe(ch)
+	collector.NewSsacliToolCollector().Describe(ch)
+	collector.NewSsacliSumCollector(e.opts).Describe(ch)
+	collector.NewSsacliBatteryCollector().Describe(ch)
+	collector.NewSsacliPhysDiskCollector("", "", e.opts).Describe(ch)
+	collector.NewSmartctlDiskCollectorWithResults(e.devicePath, "", "", 0, nil, e.opts).Describe(ch)
+	collector.NewSsacliLogDiskCollector("", "", e.opts).Describe(ch)
+	collector.NewSsacliArrayCollector("", "").Describe(ch)
+	collector.NewSsacliEnclosureCollector("").Describe(ch)
+	collector.NewSsacliEventsCollector("", e.opts).Describe(ch)
+	collector.NewSsacliDiagCollector(e.opts).Describe(ch)
+	collector.NewSummaryCollector(nil, nil, e.opts).Describe(ch)
 }
 
 // Collect sends the collected metrics from each of the collectors to
 // exporter.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
-	collector.NewSsacliSumCollector().Collect(ch)
+	collector.NewSsacliToolCollector().Collect(ch)
+
+	// Both collectors read "ctrl all show detail", run it once for them.
+	// On failure they fall back to running it themselves and report the
+	// error.
+	detail, _ := getControllerDetail()
+	collector.NewSsacliSumCollectorWithData(detail, e.opts).Collect(ch)
+	collector.NewSsacliBatteryCollectorWithData(detail).Collect(ch)
+	collector.NewSsacliDiagCollector(e.opts).Collect(ch)
 
 	slotIDs, err := getControllerSlots()
 	if err != nil {
@@ -48,43 +73,85 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	var wg sync.WaitGroup
+	var (
+		wg           sync.WaitGroup
+		controllers  []*smartarray.Controller
+		smartResults = collector.NewSmartResults()
+	)
 
 	for _, slotID := range slotIDs {
-		pdDataMap, err := getPhysicalDisksBulk(slotID)
+		pdDataMap, pdDetail, err := getPhysicalDisksBulk(slotID)
 		if err != nil {
 			log.Printf("[ERROR] failed getting bulk PD data for slot %s: %v", slotID, err)
 			continue
 		}
 
-		smartCtlIndex := 0
-		for pdID, rawData := range pdDataMap {
+		sum := controllerSum(detail, slotID)
+		linkRate := collector.ControllerLinkRate(sum.Model)
+		pds := parser.ParseSsacliPhysDisk(pdDetail)
+		spares := arraySpares(pds)
+
+		serials := physDiskSerials(pds)
+
+		for smartCtlIndex, pdID := range physDiskIDs(pdDataMap) {
 			wg.Add(1)
 			go func(sID, pID, data string, idx int) {
 				defer wg.Done()
 
 				// NEW: Pass pre-collected raw data to the collector
 				// This prevents the collector from running its own 'ssacli' command
-				collector.NewSsacliPhysDiskCollectorWithData(pID, sID, data).Collect(ch)
+				collector.NewSsacliPhysDiskCollectorWithData(pID, sID, data, linkRate, e.opts).Collect(ch)
 
 				// SMART metrics still need separate 'smartctl' calls
 				// because they talk to the disk firmware directly
-				collector.NewSmartctlDiskCollector(e.devicePath, pID, idx).Collect(ch)
-			}(slotID, pdID, rawData, smartCtlIndex)
+				collector.NewSmartctlDiskCollectorWithResults(e.devicePath, pID, serials[pID], idx, smartResults, e.opts).Collect(ch)
+			}(slotID, pdID, pdDataMap[pdID], smartCtlIndex)
+		}
+
+		wg.Add(1)
+		go func(sID string) {
+			defer wg.Done()
+			collector.NewSsacliEventsCollector(sID, e.opts).Collect(ch)
+		}(slotID)
+
+		enclosureData, err := getEnclosuresBulk(slotID)
+		if err != nil {
+			log.Printf("[ERROR] failed getting enclosure data for slot %s: %v", slotID, err)
+		} else {
+			wg.Add(1)
+			go func(sID, data string) {
+				defer wg.Done()
+				collector.NewSsacliEnclosureCollectorWithData(sID, data).Collect(ch)
+			}(slotID, enclosureData)
+		}
 
-			smartCtlIndex++
+		arrayDataMap, err := getArraysBulk(slotID)
+		if err == nil {
+			for arrayID, rawData := range arrayDataMap {
+				wg.Add(1)
+				s := spares[arrayID]
+				go func(sID, aID, data string) {
+					defer wg.Done()
+					collector.NewSsacliArrayCollectorWithData(aID, sID, data, &s).Collect(ch)
+				}(slotID, arrayID, rawData)
+			}
 		}
 
-		ldDataMap, err := getLogicalDrivesBulk(slotID)
+		ldDataMap, ldDetail, err := getLogicalDrivesBulk(slotID)
 		if err == nil {
 			for ldID, rawData := range ldDataMap {
 				wg.Add(1)
 				go func(sID, lID, data string) {
 					defer wg.Done()
-					collector.NewSsacliLogDiskCollectorWithData(lID, sID, data).Collect(ch)
+					collector.NewSsacliLogDiskCollectorWithData(lID, sID, data, e.opts).Collect(ch)
 				}(slotID, ldID, rawData)
 			}
 		}
+
+		controllers = append(controllers, buildController(detail, slotID, pdDetail, ldDetail, enclosureData))
 	}
 	wg.Wait()
+
+	// The summary uses the smartctl results, so it runs last
+	collector.NewSummaryCollector(controllers, smartResults, e.opts).Collect(ch)
 }
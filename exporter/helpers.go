@@ -1,13 +1,18 @@
 package exporter
 
 import (
-	"os/exec"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/collector"
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/pkg/smartarray"
 )
 
 func getControllerSlots() ([]string, error) {
-	out, err := exec.Command("ssacli", "ctrl", "all", "show", "status").CombinedOutput()
+	out, err := collector.RunSsacli("ctrl", "all", "show", "status")
 	if err != nil {
 		return nil, err
 	}
@@ -25,56 +30,215 @@ func getControllerSlots() ([]string, error) {
 	return slots, nil
 }
 
-func getPhysicalDisksBulk(slotID string) (map[string]string, error) {
-	out, err := exec.Command("ssacli", "ctrl", "slot="+slotID, "pd", "all", "show", "detail").CombinedOutput()
+func getControllerDetail() (string, error) {
+	out, err := collector.RunSsacli("ctrl", "all", "show", "detail")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// arrayHeaderRe matches the group headers ssacli prints before the drives
+// of every array ("Array A") and before drives in no array ("Unassigned").
+var arrayHeaderRe = regexp.MustCompile(`^(Array\s+[A-Za-z]+|Unassigned)$`)
+
+// getPhysicalDisksBulk returns one chunk per drive for the drive
+// collectors and the whole listing, which has shared spares under every
+// array they protect, for the per-array spare counts and the topology.
+func getPhysicalDisksBulk(slotID string) (map[string]string, string, error) {
+	out, err := collector.RunSsacli("ctrl", "slot="+slotID, "pd", "all", "show", "detail")
+	if err != nil {
+		return nil, "", err
+	}
+	return splitBulk(string(out), "physicaldrive "), string(out), nil
+}
+
+// getLogicalDrivesBulk returns one chunk per logical drive for the
+// collectors and the whole listing for the topology.
+func getLogicalDrivesBulk(slotID string) (map[string]string, string, error) {
+	out, err := collector.RunSsacli("ctrl", "slot="+slotID, "ld", "all", "show", "detail")
+	if err != nil {
+		return nil, "", err
+	}
+	return splitBulk(string(out), "Logical Drive: "), string(out), nil
+}
+
+func getArraysBulk(slotID string) (map[string]string, error) {
+	out, err := collector.RunSsacli("ctrl", "slot="+slotID, "array", "all", "show", "detail")
 	if err != nil {
 		return nil, err
 	}
+	return splitBulk(string(out), "Array: "), nil
+}
 
-	pdMap := make(map[string]string)
-	parts := strings.Split(string(out), "physicaldrive ")
+func getEnclosuresBulk(slotID string) (string, error) {
+	out, err := collector.RunSsacli("ctrl", "slot="+slotID, "enclosure", "all", "show", "detail")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
 
-	for i, part := range parts {
-		if i == 0 {
-			continue
+// splitBulk cuts "pd all show detail", "ld all show detail" or "array all
+// show detail" output into one chunk per drive or array, keyed by the ID
+// following marker. Each chunk starts
+// with the array header the drive was listed under, so the parsers still
+// know which array it belongs to.
+func splitBulk(out string, marker string) map[string]string {
+	var (
+		chunks = make(map[string]string)
+		header string
+		id     string
+		chunk  []string
+	)
+
+	flush := func() {
+		if id == "" {
+			return
 		}
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
+		// A spare shared by several arrays is listed under each of them,
+		// keep the first listing so the drive is exported once
+		if _, ok := chunks[id]; !ok {
+			chunks[id] = strings.Join(chunk, "\n")
 		}
-		fields := strings.Fields(part)
-		if len(fields) < 1 {
+		id = ""
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if arrayHeaderRe.MatchString(trimmed) {
+			flush()
+			header = trimmed
 			continue
 		}
-		diskID := fields[0]
-		pdMap[diskID] = "physicaldrive " + part
+
+		if strings.HasPrefix(trimmed, marker) {
+			flush()
+			fields := strings.Fields(strings.TrimPrefix(trimmed, marker))
+			if len(fields) < 1 {
+				continue
+			}
+			id = fields[0]
+			chunk = []string{header}
+		}
+
+		if id != "" {
+			chunk = append(chunk, line)
+		}
 	}
-	return pdMap, nil
+	flush()
+
+	return chunks
 }
 
-func getLogicalDrivesBulk(slotID string) (map[string]string, error) {
-	out, err := exec.Command("ssacli", "ctrl", "slot="+slotID, "ld", "all", "show", "detail").CombinedOutput()
-	if err != nil {
-		return nil, err
+// physDiskIDs returns the drive IDs of the bulk listing in port, box and
+// bay order, which is the order smartctl numbers cciss devices in.
+func physDiskIDs(pdDataMap map[string]string) []string {
+	ids := make([]string, 0, len(pdDataMap))
+	for id := range pdDataMap {
+		ids = append(ids, id)
 	}
+	slices.SortFunc(ids, comparePhysDiskIDs)
+	return ids
+}
 
-	ldMap := make(map[string]string)
-	parts := strings.Split(string(out), "Logical Drive: ")
-
-	for i, part := range parts {
-		if i == 0 {
+// comparePhysDiskIDs orders drive IDs such as "1I:1:2" and "1I:1:10" field
+// by field, numerically where both fields are numbers.
+func comparePhysDiskIDs(a string, b string) int {
+	fa, fb := strings.Split(a, ":"), strings.Split(b, ":")
+	for i := 0; i < len(fa) && i < len(fb); i++ {
+		na, errA := strconv.Atoi(fa[i])
+		nb, errB := strconv.Atoi(fb[i])
+		if errA == nil && errB == nil {
+			if na != nb {
+				return na - nb
+			}
 			continue
 		}
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
+		if c := strings.Compare(fa[i], fb[i]); c != 0 {
+			return c
 		}
-		fields := strings.Fields(part)
-		if len(fields) < 1 {
+	}
+	return len(fa) - len(fb)
+}
+
+// physDiskSerials maps drive IDs to the serial numbers ssacli reports.
+func physDiskSerials(pds *parser.SsacliPhysDisk) map[string]string {
+	serials := make(map[string]string)
+	for _, disk := range pds.SsacliPhysDiskData {
+		serials[disk.ID] = disk.SN
+	}
+	return serials
+}
+
+// arraySpares counts the spare drives listed under every array. pds must
+// be the whole listing, a shared spare counts for every array it is
+// listed under.
+func arraySpares(pds *parser.SsacliPhysDisk) map[string]collector.ArraySpares {
+	spares := make(map[string]collector.ArraySpares)
+	for _, disk := range pds.SsacliPhysDiskData {
+		if disk.Role() != parser.RoleSpare {
 			continue
 		}
-		ldID := fields[0]
-		ldMap[ldID] = "Logical Drive: " + part
+		s := spares[disk.Array]
+		s.Total++
+		if disk.Status == "OK" {
+			s.Healthy++
+		}
+		spares[disk.Array] = s
 	}
-	return ldMap, nil
+	return spares
+}
+
+// controllerSum returns the "ctrl all show detail" section of a
+// controller, falling back to just its slot when the detail is missing.
+func controllerSum(detail string, slotID string) parser.SsacliSumData {
+	for _, sum := range parser.ParseSsacliSum(detail).SsacliSumData {
+		if sum.SlotID == slotID {
+			return sum
+		}
+	}
+	slot, _ := strconv.Atoi(slotID)
+	return parser.SsacliSumData{SlotID: slotID, Slot: int64(slot)}
+}
+
+// controllerDetail cuts the section of a controller out of "ctrl all show
+// detail" output, empty when the controller is not listed.
+func controllerDetail(detail string, slotID string) string {
+	var (
+		sections []string
+		section  []string
+	)
+	for _, line := range strings.Split(detail, "\n") {
+		// Every section starts with a header such as
+		// "Smart Array P440ar in Slot 0 (Embedded)"
+		if strings.Contains(line, " in Slot ") && !strings.Contains(line, ": ") {
+			sections = append(sections, strings.Join(section, "\n"))
+			section = nil
+		}
+		section = append(section, line)
+	}
+	sections = append(sections, strings.Join(section, "\n"))
+
+	for _, s := range sections {
+		for _, sum := range parser.ParseSsacliSum(s).SsacliSumData {
+			if sum.SlotID == slotID {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// buildController assembles the topology of a controller from the raw
+// listings used by the collectors. Any of them may be empty.
+func buildController(detail string, slotID string, pdDetail string, ldDetail string, enclosureData string) *smartarray.Controller {
+	// Values that could not be parsed are already reported by the sum
+	// collector, which reads the same detail
+	c, _ := smartarray.ParseController(controllerDetail(detail, slotID), pdDetail, ldDetail)
+	// The slot is known even when the detail section is missing
+	c.Slot, _ = strconv.Atoi(slotID)
+	c.AddEnclosureDetail(enclosureData)
+	return c
 }
@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+)
+
+func TestSplitBulkPhysDisks(t *testing.T) {
+	out := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      physicaldrive 1I:1:1
+         Port: 1I
+         Status: OK
+
+      physicaldrive 1I:1:2
+         Port: 1I
+         Status: OK
+
+   Unassigned
+
+      physicaldrive 1I:1:3
+         Port: 1I
+         Status: OK
+`
+
+	chunks := splitBulk(out, "physicaldrive ")
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %v", len(chunks), chunks)
+	}
+
+	// Every chunk starts with the header of the group it was listed under
+	for id, header := range map[string]string{"1I:1:1": "Array A", "1I:1:2": "Array A", "1I:1:3": "Unassigned"} {
+		lines := strings.Split(chunks[id], "\n")
+		if lines[0] != header || strings.TrimSpace(lines[1]) != "physicaldrive "+id {
+			t.Errorf("unexpected chunk for %s: %q", id, chunks[id])
+		}
+	}
+	if strings.Contains(chunks["1I:1:2"], "Unassigned") {
+		t.Errorf("chunk for 1I:1:2 runs into the next group: %q", chunks["1I:1:2"])
+	}
+}
+
+func TestSplitBulkArrays(t *testing.T) {
+	out := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array: A
+      Interface Type: SAS
+      Status: OK
+
+   Array: B
+      Interface Type: SAS
+      Status: OK
+`
+
+	chunks := splitBulk(out, "Array: ")
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d: %v", len(chunks), chunks)
+	}
+	if !strings.Contains(chunks["A"], "Array: A") || strings.Contains(chunks["A"], "Array: B") {
+		t.Errorf("unexpected chunk for A: %q", chunks["A"])
+	}
+}
+
+func TestSplitBulkSharedSpare(t *testing.T) {
+	out := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      physicaldrive 1I:1:1
+         Status: OK
+         Drive Type: Data Drive
+
+      physicaldrive 1I:1:4
+         Status: OK
+         Drive Type: Spare Drive
+
+   Array B
+
+      physicaldrive 1I:1:2
+         Status: OK
+         Drive Type: Data Drive
+
+      physicaldrive 1I:1:4
+         Status: OK
+         Drive Type: Spare Drive
+`
+
+	chunks := splitBulk(out, "physicaldrive ")
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %v", len(chunks), chunks)
+	}
+
+	// The shared spare is exported once, under the first array
+	pds := parser.ParseSsacliPhysDisk(chunks["1I:1:4"]).SsacliPhysDiskData
+	if len(pds) != 1 || pds[0].Array != "A" {
+		t.Fatalf("unexpected listings of the shared spare: %+v", pds)
+	}
+
+	// but counts for every array it protects
+	spares := arraySpares(parser.ParseSsacliPhysDisk(out))
+	for _, array := range []string{"A", "B"} {
+		if s := spares[array]; s.Total != 1 || s.Healthy != 1 {
+			t.Errorf("array %s: expected 1 healthy spare, got %+v", array, s)
+		}
+	}
+
+	// The topology holds the spare once, listed under both arrays
+	c := buildController("", "0", out, "", "")
+	if n := len(c.PhysicalDrives()); n != 3 {
+		t.Errorf("expected 3 physical drives, got %d", n)
+	}
+	spare := c.PhysicalDrive("1I:1:4")
+	for _, a := range c.Arrays {
+		listed := false
+		for _, pd := range a.PhysicalDrives {
+			listed = listed || pd == spare
+		}
+		if len(a.PhysicalDrives) != 2 || !listed {
+			t.Errorf("array %s should list its data drive and the spare, got %d drives", a.ID, len(a.PhysicalDrives))
+		}
+	}
+}
+
+func TestControllerDetail(t *testing.T) {
+	detail := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   Serial Number: PDNLH0BRH7V1OP
+
+Smart Array P840 in Slot 3
+   Slot: 3
+   Serial Number: PDNNF0ARH8Z0AS
+`
+
+	c := buildController(detail, "3", "", "", "")
+	if c.Slot != 3 || c.Model != "Smart Array P840" || c.SerialNumber != "PDNNF0ARH8Z0AS" {
+		t.Errorf("unexpected controller for slot 3: %+v", c)
+	}
+
+	// A controller missing from the detail still gets its slot
+	c = buildController(detail, "5", "", "", "")
+	if c.Slot != 5 || c.Model != "" {
+		t.Errorf("unexpected controller for slot 5: %+v", c)
+	}
+}
+
+func TestPhysDiskIDsBayOrder(t *testing.T) {
+	pdDataMap := map[string]string{
+		"2I:1:1":  "",
+		"1I:1:10": "",
+		"1I:1:2":  "",
+		"1I:2:1":  "",
+		"1I:1:1":  "",
+	}
+
+	want := []string{"1I:1:1", "1I:1:2", "1I:1:10", "1I:2:1", "2I:1:1"}
+	// Map order is random, so run a few times
+	for range 10 {
+		got := physDiskIDs(pdDataMap)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
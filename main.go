@@ -4,22 +4,72 @@ import (
 	"flag"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/collector"
 	"github.com/CloudOpsKit/smartctl_ssacli_exporter/exporter"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 var (
-	listenAddr  = flag.String("listen", ":9633", "address for exporter")
-	metricsPath = flag.String("path", "/metrics", "URL path for surfacing collected metrics")
-	devicePath  = flag.String("device", "/dev/sda", "Path to the raid controller device (e.g. /dev/sda or /dev/sg0)")
+	listenAddr   = flag.String("listen", ":9633", "address for exporter")
+	metricsPath  = flag.String("path", "/metrics", "URL path for surfacing collected metrics")
+	devicePath   = flag.String("device", "/dev/sda", "Path to the raid controller device (e.g. /dev/sda or /dev/sg0)")
+	ssacliPath   = flag.String("ssacli.path", "", "Path to the Smart Array CLI, empty searches PATH for ssacli, hpssacli and hpacucli")
+	lockFile     = flag.String("ssacli.lock-file", "", "File to flock around every Smart Array CLI run, shared with other tools using the controller")
+	runTimeout   = flag.Duration("ssacli.timeout", 30*time.Second, "Time a Smart Array CLI run, including the wait for other runs, may take before it is killed, 0 disables the limit")
+	legacy       = flag.Bool("metrics.legacy", false, "Export status metrics in the pre-v2 shape with volatile values as labels")
+	thresholds   = flag.String("temperature.thresholds", "", "Path to a JSON file with per-model drive temperature thresholds")
+	advisories   = flag.String("firmware.advisories", "", "Path to a JSON database of drive firmware advisories")
+	logEvents    = flag.Bool("events.log", false, "Write new controller event log entries to the exporter log")
+	reallocated  = flag.Float64("replace.reallocated-sectors", 100, "Reallocated sectors above which a drive is recommended for replacement, 0 disables the check")
+	diagFile     = flag.String("diag.file", "", "Path to an Array Diagnostic Utility report (ssacli ctrl all diag) to export drive error counters from")
+	diagInterval = flag.Duration("diag.interval", 0, "Regenerate the diag.file report once it is older than this, 0 only reads an existing report")
 )
 
 func main() {
 	flag.Parse()
 
-	prometheus.MustRegister(exporter.New(*devicePath))
+	collector.SetLockFile(*lockFile)
+	collector.SetRunTimeout(*runTimeout)
+
+	tool, err := collector.DiscoverTool(*ssacliPath)
+	switch {
+	case err == nil:
+		collector.SetTool(tool)
+		log.Printf("Using %s (version %q)", tool.Path, tool.Version)
+	case *ssacliPath != "":
+		log.Fatalf("Cannot use Smart Array CLI: %s", err)
+	default:
+		log.Printf("[ERROR] Smart Array CLI not found, running ssacli anyway: %s", err)
+	}
+
+	opts := collector.Options{
+		Legacy:                  *legacy,
+		ReallocatedSectorsLimit: *reallocated,
+		LogEvents:               *logEvents,
+		DiagFile:                *diagFile,
+		DiagInterval:            *diagInterval,
+	}
+
+	if *thresholds != "" {
+		t, err := collector.LoadTemperatureThresholds(*thresholds)
+		if err != nil {
+			log.Fatalf("Cannot load temperature thresholds: %s", err)
+		}
+		opts.TemperatureThresholds = t
+	}
+
+	if *advisories != "" {
+		a, err := collector.LoadFirmwareAdvisories(*advisories)
+		if err != nil {
+			log.Fatalf("Cannot load firmware advisories: %s", err)
+		}
+		opts.FirmwareAdvisories = a
+	}
+
+	prometheus.MustRegister(exporter.NewWithOptions(*devicePath, opts))
 
 	http.Handle(*metricsPath, promhttp.Handler())
 
@@ -1,25 +1,35 @@
 package parser
 
 import (
-	"log"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
-func toINT(s string) int64 {
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		log.Fatalln(err)
+// arrayHeaderRe matches the array group headers ssacli prints before the
+// drives of each array: "Array A" in pd/ld listings, "Array: A" in
+// array and config detail output.
+var arrayHeaderRe = regexp.MustCompile(`^Array:?\s+([A-Za-z]+)$`)
+
+// parseArrayHeader reports whether line opens a new drive group and, if
+// so, the array it belongs to. Drives listed under "Unassigned" belong to
+// no array and yield an empty name.
+func parseArrayHeader(line string) (string, bool) {
+	if line == "Unassigned" {
+		return "", true
 	}
-	return int64(i)
+	if m := arrayHeaderRe.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+	return "", false
 }
 
-func toFLO(s string) float64 {
-	i, err := strconv.ParseFloat(s, 64)
-	if err != nil {
-		log.Fatalln(err)
-	}
-	return float64(i)
+func toINT(s string) (int64, error) {
+	return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+}
+
+func toFLO(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
 }
 
 func trim(s string) string {
@@ -47,3 +57,45 @@ func parseSmartRawValue(s string) *float64 {
 	// 3. Return pointer
 	return &val
 }
+
+// parsePercent converts ssacli progress such as "43% complete" or
+// "99.74%" into a ratio between 0 and 1.
+func parsePercent(s string) *float64 {
+	idx := strings.Index(s, "%")
+	if idx == -1 {
+		return nil
+	}
+
+	val, err := strconv.ParseFloat(strings.TrimSpace(s[:idx]), 64)
+	if err != nil {
+		return nil
+	}
+
+	val /= 100
+	return &val
+}
+
+// parseSize is ParseBytes for optional parser fields, nil when s is not a
+// capacity.
+func parseSize(s string, base float64) *float64 {
+	val, err := ParseBytes(s, base)
+	if err != nil {
+		return nil
+	}
+	return &val
+}
+
+// parseBool converts the True/False and Yes/No flags ssacli prints into 1
+// or 0.
+func parseBool(s string) *float64 {
+	var val float64
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "enabled":
+		val = 1
+	case "false", "no", "disabled":
+		val = 0
+	default:
+		return nil
+	}
+	return &val
+}
@@ -16,6 +16,9 @@ type SmartctlDiskDataInfo struct {
 	SN       string
 	RotRate  string
 	FromFact string
+	// Health is the overall verdict of "smartctl -H", "PASSED" for ATA
+	// and "OK" for SCSI drives, empty when not requested.
+	Health string
 }
 
 // SmartctlDiskDataAttr comment
@@ -58,6 +61,7 @@ func ParseSmartctlDisk(s string) *SmartctlDisk {
 			dataAtr = parseSmartctlDiskAtr(section)
 		}
 	}
+	dataInfo.Health = parseSmartctlHealth(s)
 
 	data := SmartctlDisk{
 		SmartctlDiskDataAttr: []SmartctlDiskDataAttr{
@@ -186,3 +190,20 @@ func parseSmartctlDiskAtr(s string) SmartctlDiskDataAttr {
 
 	return tmp
 }
+
+// parseSmartctlHealth returns the "smartctl -H" verdict, printed as
+// "SMART overall-health self-assessment test result: PASSED" for ATA and
+// "SMART Health Status: OK" for SCSI drives.
+func parseSmartctlHealth(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(line), ": ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "SMART overall-health self-assessment test result", "SMART Health Status":
+			return strings.TrimSpace(kv[1])
+		}
+	}
+	return ""
+}
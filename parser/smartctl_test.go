@@ -47,3 +47,30 @@ ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_
 		t.Errorf("GrownDefects should be nil, got %v", attrs.GrownDefects)
 	}
 }
+
+func TestParseSmartctlDiskHealth(t *testing.T) {
+	ata := `
+=== START OF INFORMATION SECTION ===
+Device Model:     MB2000GCWDA
+=== START OF READ SMART DATA SECTION ===
+SMART overall-health self-assessment test result: FAILED!
+Drive failure expected in less than 24 hours. SAVE ALL DATA.
+`
+	scsi := `
+=== START OF READ SMART DATA SECTION ===
+SMART Health Status: OK
+Elements in grown defect list: 12
+`
+
+	if h := ParseSmartctlDisk(ata).SmartctlDiskDataInfo[0].Health; h != "FAILED!" {
+		t.Errorf("ATA health: expected FAILED!, got %q", h)
+	}
+
+	data := ParseSmartctlDisk(scsi)
+	if h := data.SmartctlDiskDataInfo[0].Health; h != "OK" {
+		t.Errorf("SCSI health: expected OK, got %q", h)
+	}
+	if g := data.SmartctlDiskDataAttr[0].GrownDefects; g == nil || *g != 12 {
+		t.Errorf("GrownDefects: expected 12, got %v", g)
+	}
+}
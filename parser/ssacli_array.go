@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"strings"
+)
+
+// SsacliArray data structure for output
+type SsacliArray struct {
+	SsacliArrayData []SsacliArrayData
+}
+
+// SsacliArrayData data structure for output
+type SsacliArrayData struct {
+	ID               string
+	InterfaceType    string
+	UnusedSpace      string
+	UnusedSpaceBytes float64
+	UsedSpace        string
+	UsedSpaceBytes   float64
+	Status           string
+	ArrayType        string
+	SpareType        string
+	SmartPath        string
+}
+
+// ParseSsacliArray return specific metric
+func ParseSsacliArray(s string) *SsacliArray {
+	var (
+		data []SsacliArrayData
+		tmp  SsacliArrayData
+	)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		if name, ok := parseArrayHeader(line); ok {
+			if tmp.ID != "" {
+				data = append(data, tmp)
+			}
+			tmp = SsacliArrayData{ID: name}
+			continue
+		}
+
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) == 2 {
+			key := strings.TrimSpace(kv[0])
+			val := strings.TrimSpace(kv[1])
+
+			switch key {
+			case "Interface Type":
+				tmp.InterfaceType = val
+			case "Unused Space":
+				tmp.UnusedSpace = val
+				tmp.UnusedSpaceBytes, _ = ParseBytes(val, BinaryBase)
+			case "Used Space":
+				tmp.UsedSpace = val
+				tmp.UsedSpaceBytes, _ = ParseBytes(val, BinaryBase)
+			case "Status":
+				tmp.Status = val
+			case "Array Type":
+				tmp.ArrayType = val
+			case "Spare Type":
+				tmp.SpareType = val
+			case "HPE SSD Smart Path", "HP SSD Smart Path", "Smart Path":
+				tmp.SmartPath = val
+			}
+		}
+	}
+
+	if tmp.ID != "" {
+		data = append(data, tmp)
+	}
+
+	return &SsacliArray{SsacliArrayData: data}
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliArray(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array: A
+      Interface Type: SAS
+      Unused Space: 0 MB (0.00%)
+      Used Space: 1.09 TB (100.00%)
+      Status: OK
+      MultiDomain Status: OK
+      Array Type: Data
+      Spare Type: dedicated
+      HPE SSD Smart Path: disable
+
+   Array: B
+      Interface Type: Solid State SATA
+      Unused Space: 100.0 GB (10.00%)
+      Used Space: 900.0 GB (90.00%)
+      Status: Failed Physical Drive
+      Array Type: Data
+      HPE SSD Smart Path: enable
+`
+
+	data := ParseSsacliArray(rawOutput).SsacliArrayData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 arrays, got %d", len(data))
+	}
+
+	a := data[0]
+	if a.ID != "A" || a.InterfaceType != "SAS" || a.Status != "OK" || a.ArrayType != "Data" || a.SpareType != "dedicated" {
+		t.Errorf("unexpected array A: %+v", a)
+	}
+	if a.UnusedSpaceBytes != 0 || a.UsedSpaceBytes != 1.09*(1<<40) {
+		t.Errorf("unexpected space for array A: used %v, unused %v", a.UsedSpaceBytes, a.UnusedSpaceBytes)
+	}
+
+	b := data[1]
+	if b.ID != "B" || b.Status != "Failed Physical Drive" || b.SpareType != "" {
+		t.Errorf("unexpected array B: %+v", b)
+	}
+	if b.UnusedSpaceBytes != 100*(1<<30) {
+		t.Errorf("unexpected unused space for array B: %v", b.UnusedSpaceBytes)
+	}
+}
+
+func TestParseSsacliArraySmartPath(t *testing.T) {
+	rawOutput := `
+   Array: A
+      Interface Type: Solid State SAS
+      Status: OK
+      HPE SSD Smart Path: enable
+
+   Array: B
+      Interface Type: Solid State SATA
+      Status: OK
+      HP SSD Smart Path: disable
+
+   Array: C
+      Interface Type: SAS
+      Status: OK
+`
+
+	data := ParseSsacliArray(rawOutput).SsacliArrayData
+	if len(data) != 3 {
+		t.Fatalf("expected 3 arrays, got %d", len(data))
+	}
+
+	for i, want := range []string{"enable", "disable", ""} {
+		if data[i].SmartPath != want {
+			t.Errorf("array %s: expected Smart Path %q, got %q", data[i].ID, want, data[i].SmartPath)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"strings"
+)
+
+// SsacliBattery data structure for output
+type SsacliBattery struct {
+	SsacliBatteryData []SsacliBatteryData
+}
+
+// SsacliBatteryData holds the battery/capacitor module of one controller
+type SsacliBatteryData struct {
+	SlotID       string
+	Count        *float64
+	Status       string
+	ChargeStatus string
+	Temperature  *float64
+}
+
+// batteryKeys maps the spellings used by the different ssacli, hpssacli
+// and hpacucli versions onto one field each. Keys are compared with runs of
+// whitespace collapsed, which also covers the "Capacitor Temperature  (C)"
+// typo printed by some firmware.
+var batteryKeys = map[string]string{
+	"Number of Batteries/Capacitors":    "count",
+	"Battery/Capacitor Count":           "count",
+	"Battery Pack Count":                "count",
+	"Battery/Capacitor Status":          "status",
+	"Battery Status":                    "status",
+	"Capacitor Status":                  "status",
+	"Battery/Capacitor Charge Status":   "charge",
+	"Battery Charge Status":             "charge",
+	"Capacitor Charge Status":           "charge",
+	"Battery/Capacitor Temperature (C)": "temperature",
+	"Capacitor Temperature (C)":         "temperature",
+	"Battery Temperature (C)":           "temperature",
+}
+
+// ParseSsacliBattery parses the battery/capacitor details out of
+// "ctrl all show detail"
+func ParseSsacliBattery(s string) *SsacliBattery {
+	var (
+		data []SsacliBatteryData
+		tmp  SsacliBatteryData
+	)
+
+	for _, line := range strings.Split(s, "\n") {
+		kv := strings.SplitN(strings.TrimSpace(line), ": ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		key := strings.Join(strings.Fields(kv[0]), " ")
+		val := strings.TrimSpace(kv[1])
+
+		if key == "Slot" {
+			if tmp.SlotID != "" {
+				data = append(data, tmp)
+			}
+			tmp = SsacliBatteryData{SlotID: val}
+			continue
+		}
+
+		switch batteryKeys[key] {
+		case "count":
+			tmp.Count = parseSmartRawValue(val)
+		case "status":
+			tmp.Status = val
+		case "charge":
+			tmp.ChargeStatus = val
+		case "temperature":
+			tmp.Temperature = parseSmartRawValue(val)
+		}
+	}
+
+	if tmp.SlotID != "" {
+		data = append(data, tmp)
+	}
+
+	return &SsacliBattery{SsacliBatteryData: data}
+}
@@ -0,0 +1,54 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliBatterySpellings(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   Cache Backup Power Source: Batteries
+   Battery/Capacitor Count: 1
+   Battery/Capacitor Status: OK
+   Capacitor Temperature  (C): 31
+
+Smart Array P410i in Slot 1
+   Slot: 1
+   No-Battery Write Cache: Disabled
+   Number of Batteries/Capacitors: 2
+   Battery Status: Recharging
+   Battery Charge Status: Charging
+   Battery Temperature (C): 28
+
+Smart Array P420 in Slot 2
+   Slot: 2
+   Cache Backup Power Source: None
+`
+
+	data := ParseSsacliBattery(rawOutput).SsacliBatteryData
+	if len(data) != 3 {
+		t.Fatalf("expected 3 controllers, got %d", len(data))
+	}
+
+	// 1. Current ssacli spelling, including the double space typo
+	if data[0].Count == nil || *data[0].Count != 1 || data[0].Status != "OK" {
+		t.Errorf("unexpected battery for slot 0: %+v", data[0])
+	}
+	if data[0].Temperature == nil || *data[0].Temperature != 31 {
+		t.Errorf("Temperature: expected 31, got %v", data[0].Temperature)
+	}
+
+	// 2. Older spellings map onto the same fields
+	if data[1].SlotID != "1" || data[1].Count == nil || *data[1].Count != 2 || data[1].Status != "Recharging" || data[1].ChargeStatus != "Charging" {
+		t.Errorf("unexpected battery for slot 1: %+v", data[1])
+	}
+	if data[1].Temperature == nil || *data[1].Temperature != 28 {
+		t.Errorf("Temperature: expected 28, got %v", data[1].Temperature)
+	}
+
+	// 3. Controllers without a battery report nothing
+	if data[2].SlotID != "2" || data[2].Count != nil || data[2].Status != "" || data[2].Temperature != nil {
+		t.Errorf("unexpected battery for slot 2: %+v", data[2])
+	}
+}
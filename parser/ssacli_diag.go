@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ADUReport holds what the exporter uses from the XML report in the zip
+// written by "ssacli ctrl all diag file=report.zip"
+type ADUReport struct {
+	Controllers []ADUController
+}
+
+// ADUController is an array controller found in the report
+type ADUController struct {
+	Slot         string
+	Model        string
+	SerialNumber string
+	Drives       []ADUDrive
+}
+
+// ADUDrive is a physical drive found in the report
+type ADUDrive struct {
+	ID              string
+	PHYErrors       []ADUPHYCounter
+	ErrorLogEntries int
+}
+
+// ADUPHYCounter is one SAS PHY error counter, e.g. PHY 1 "invalid_dword"
+type ADUPHYCounter struct {
+	PHY     int
+	Counter string
+	Value   float64
+}
+
+// aduDevice mirrors the nested <Device> elements of the report. Only the
+// parts the exporter reads are declared.
+type aduDevice struct {
+	Type           string         `xml:"deviceType,attr"`
+	Name           string         `xml:"marketingName,attr"`
+	MetaProperties []aduProperty  `xml:"MetaProperty"`
+	Properties     []aduProperty  `xml:"Property"`
+	Structures     []aduStructure `xml:"Structure"`
+	Devices        []aduDevice    `xml:"Device"`
+}
+
+type aduStructure struct {
+	ID         string         `xml:"id,attr"`
+	Properties []aduProperty  `xml:"Property"`
+	Structures []aduStructure `xml:"Structure"`
+}
+
+type aduProperty struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type aduRoot struct {
+	Devices []aduDevice `xml:"Device"`
+}
+
+// phyCounterRe matches PHY error counters such as
+// "PHY 1 Invalid DWORD Count"
+var phyCounterRe = regexp.MustCompile(`(?i)^PHY\s*(\d+)\s+(.+?)\s+Count$`)
+
+// aduReportName is the XML report in a diag zip. The archive holds other
+// XML files that are not reports.
+const aduReportName = "ADUReport.xml"
+
+// ParseADUArchive reads the XML report of a diag zip
+func ParseADUArchive(r io.ReaderAt, size int64) (*ADUReport, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		if !strings.EqualFold(path.Base(f.Name), aduReportName) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ParseADUReport(rc)
+	}
+
+	return nil, fmt.Errorf("no %s in diag archive", aduReportName)
+}
+
+// ParseADUReport parses the XML diagnostic report
+func ParseADUReport(r io.Reader) (*ADUReport, error) {
+	var root aduRoot
+	if err := xml.NewDecoder(r).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	report := &ADUReport{}
+	var walk func(devices []aduDevice)
+	walk = func(devices []aduDevice) {
+		for _, d := range devices {
+			if aduDeviceType(d.Type) == "arraycontroller" {
+				report.Controllers = append(report.Controllers, newADUController(d))
+				continue
+			}
+			walk(d.Devices)
+		}
+	}
+	walk(root.Devices)
+
+	return report, nil
+}
+
+func newADUController(d aduDevice) ADUController {
+	c := ADUController{
+		Model:        d.Name,
+		Slot:         aduMeta(d, "Slot"),
+		SerialNumber: aduMeta(d, "Serial Number"),
+	}
+
+	var walk func(devices []aduDevice)
+	walk = func(devices []aduDevice) {
+		for _, child := range devices {
+			if aduDeviceType(child.Type) == "physicaldrive" {
+				c.Drives = append(c.Drives, newADUDrive(child))
+			}
+			walk(child.Devices)
+		}
+	}
+	walk(d.Devices)
+
+	return c
+}
+
+func newADUDrive(d aduDevice) ADUDrive {
+	drive := ADUDrive{ID: aduMeta(d, "Drive ID")}
+	if drive.ID == "" {
+		// "Physical Drive 1I:1:1"
+		if fields := strings.Fields(d.Name); len(fields) > 0 {
+			drive.ID = fields[len(fields)-1]
+		}
+	}
+
+	for _, p := range d.Properties {
+		m := phyCounterRe.FindStringSubmatch(p.ID)
+		if m == nil {
+			continue
+		}
+		phy, _ := strconv.Atoi(m[1])
+		val, err := parseADUCount(p.Value)
+		if err != nil {
+			continue
+		}
+		drive.PHYErrors = append(drive.PHYErrors, ADUPHYCounter{
+			PHY:     phy,
+			Counter: strings.ReplaceAll(strings.ToLower(m[2]), " ", "_"),
+			Value:   float64(val),
+		})
+	}
+
+	for _, s := range d.Structures {
+		if strings.Contains(strings.ToLower(s.ID), "error log") {
+			drive.ErrorLogEntries += len(s.Structures)
+		}
+	}
+
+	return drive
+}
+
+// parseADUCount reads counters printed as "0x0000001a" or "12". Decimal
+// values may be zero padded, so only an explicit 0x prefix means hex.
+func parseADUCount(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if hex, ok := strings.CutPrefix(strings.ToLower(s), "0x"); ok {
+		return strconv.ParseUint(hex, 16, 64)
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// aduDeviceType normalizes "Array Controller" and "ArrayController"
+func aduDeviceType(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, " ", ""))
+}
+
+func aduMeta(d aduDevice, id string) string {
+	for _, p := range d.MetaProperties {
+		if p.ID == id {
+			return p.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseADUArchive(t *testing.T) {
+	f, err := os.Open("testdata/adu_report.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The archive lists SlotInfo.xml before the report
+	report, err := ParseADUArchive(f, st.Size())
+	if err != nil {
+		t.Fatalf("ParseADUArchive: %v", err)
+	}
+	if len(report.Controllers) != 1 {
+		t.Fatalf("expected 1 controller, got %d", len(report.Controllers))
+	}
+
+	c := report.Controllers[0]
+	if c.Slot != "0" || c.Model != "Smart Array P440ar" || c.SerialNumber != "PDNLH0BRH8Q1YZ" {
+		t.Errorf("unexpected controller: %+v", c)
+	}
+	if len(c.Drives) != 2 {
+		t.Fatalf("expected 2 drives, got %d", len(c.Drives))
+	}
+
+	// 1. PHY counters are read from hex values and keyed by PHY number
+	d := c.Drives[0]
+	if d.ID != "1I:1:1" || d.ErrorLogEntries != 2 {
+		t.Errorf("unexpected drive: ID %q, error log entries %d", d.ID, d.ErrorLogEntries)
+	}
+	want := []ADUPHYCounter{
+		{1, "invalid_dword", 0},
+		{1, "running_disparity_error", 3},
+		{1, "loss_of_dword_sync", 1},
+		{1, "phy_reset_problem", 0},
+		{2, "invalid_dword", 26},
+	}
+	if len(d.PHYErrors) != len(want) {
+		t.Fatalf("expected %d PHY counters, got %+v", len(want), d.PHYErrors)
+	}
+	for i, w := range want {
+		if d.PHYErrors[i] != w {
+			t.Errorf("PHY counter %d: expected %+v, got %+v", i, w, d.PHYErrors[i])
+		}
+	}
+
+	// 2. Drives below an enclosure fall back to the ID in their name, and
+	// zero padded decimal values are not read as octal
+	d = c.Drives[1]
+	if d.ID != "1I:1:2" || d.ErrorLogEntries != 0 || len(d.PHYErrors) != 1 || d.PHYErrors[0].Value != 12 {
+		t.Errorf("unexpected drive: %+v", d)
+	}
+}
+
+func TestParseADUCount(t *testing.T) {
+	for in, want := range map[string]uint64{
+		"0x0000001a": 26,
+		"0X1A":       26,
+		"12":         12,
+		"0012":       12,
+		"010":        10,
+	} {
+		got, err := parseADUCount(in)
+		if err != nil || got != want {
+			t.Errorf("parseADUCount(%q): expected %d, got %d (%v)", in, want, got, err)
+		}
+	}
+	if _, err := parseADUCount("n/a"); err == nil {
+		t.Error("expected an error for a non numeric value")
+	}
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SsacliEnclosure data structure for output
+type SsacliEnclosure struct {
+	SsacliEnclosureData []SsacliEnclosureData
+}
+
+// SsacliEnclosureData holds one drive cage or external enclosure
+type SsacliEnclosureData struct {
+	Name              string
+	Port              string
+	Box               string
+	Status            string
+	Location          string
+	VendorID          string
+	SerialNumber      string
+	Firmware          string
+	DriveBays         *float64
+	FanStatus         string
+	TemperatureStatus string
+	PowerSupplyStatus string
+	// Temperatures maps sensor names such as "Temperature Sensor 1" to
+	// degrees Celsius.
+	Temperatures map[string]float64
+}
+
+// enclosureHeaderRe matches "Internal Drive Cage at Port 1I, Box 1, OK" and
+// "Storage Enclosure at Port 1E, Box 1, OK"
+var enclosureHeaderRe = regexp.MustCompile(`^(.+) at Port (\S+), Box (\d+), (.+)$`)
+
+// ParseSsacliEnclosure parses "ctrl slot=N enclosure all show detail"
+func ParseSsacliEnclosure(s string) *SsacliEnclosure {
+	var (
+		data []SsacliEnclosureData
+		tmp  *SsacliEnclosureData
+	)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		if m := enclosureHeaderRe.FindStringSubmatch(line); m != nil {
+			data = append(data, SsacliEnclosureData{
+				Name:         m[1],
+				Port:         m[2],
+				Box:          m[3],
+				Status:       m[4],
+				Temperatures: map[string]float64{},
+			})
+			tmp = &data[len(data)-1]
+			continue
+		}
+
+		// Drives listed below the enclosure are parsed elsewhere
+		if tmp == nil || line == "Physical Drives" {
+			tmp = nil
+			continue
+		}
+
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		val := strings.TrimSpace(kv[1])
+
+		switch key {
+		case "Fan Status":
+			tmp.FanStatus = val
+		case "Temperature Status":
+			tmp.TemperatureStatus = val
+		case "Power Supply Status":
+			tmp.PowerSupplyStatus = val
+		case "Drive Bays":
+			tmp.DriveBays = parseSmartRawValue(val)
+		case "Location":
+			tmp.Location = val
+		case "Vendor ID":
+			tmp.VendorID = val
+		case "Serial Number":
+			tmp.SerialNumber = val
+		case "Firmware Version":
+			tmp.Firmware = val
+		default:
+			if sensor, ok := enclosureTemperature(key); ok {
+				if fields := strings.Fields(val); len(fields) > 0 {
+					if t := parseSmartRawValue(strings.TrimSuffix(fields[0], "C")); t != nil {
+						tmp.Temperatures[sensor] = *t
+					}
+				}
+			}
+		}
+	}
+
+	return &SsacliEnclosure{SsacliEnclosureData: data}
+}
+
+// enclosureTemperature reports whether key names a temperature reading,
+// "Temperature Sensor 1" or "Box Temperature (C)", and returns the sensor
+// name without the unit.
+func enclosureTemperature(key string) (string, bool) {
+	if !strings.Contains(key, "Temperature") || key == "Temperature Status" {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimSuffix(key, "(C)")), true
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliEnclosure(t *testing.T) {
+	rawOutput := `
+Smart Array P840 in Slot 1
+
+   Internal Drive Cage at Port 1I, Box 1, OK
+
+      Fan Status: OK
+      Temperature Status: OK
+      Power Supply Status: Redundant
+      Drive Bays: 4
+      Port: 1I
+      Box: 1
+      Location: Internal
+
+   Physical Drives
+      physicaldrive 1I:1:1 (port 1I:box 1:bay 1, SAS HDD, 600 GB, OK)
+
+   Storage Enclosure at Port 1E, Box 1, OK
+
+      Fan Status: Failed
+      Temperature Status: OK
+      Power Supply Status: Not Redundant
+      Vendor ID: HP
+      Serial Number: 2M2345678J
+      Firmware Version: 4.34
+      Drive Bays: 12
+      Port: 1E
+      Box: 1
+      Location: External
+      Temperature Sensor 1: 24 C
+      Temperature Sensor 2: 31C
+`
+
+	data := ParseSsacliEnclosure(rawOutput).SsacliEnclosureData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 enclosures, got %d", len(data))
+	}
+
+	cage := data[0]
+	if cage.Name != "Internal Drive Cage" || cage.Port != "1I" || cage.Box != "1" || cage.Status != "OK" || cage.Location != "Internal" {
+		t.Errorf("unexpected drive cage: %+v", cage)
+	}
+	if cage.DriveBays == nil || *cage.DriveBays != 4 || cage.PowerSupplyStatus != "Redundant" {
+		t.Errorf("unexpected drive cage details: bays %v, power %q", cage.DriveBays, cage.PowerSupplyStatus)
+	}
+
+	jbod := data[1]
+	if jbod.FanStatus != "Failed" || jbod.PowerSupplyStatus != "Not Redundant" || jbod.SerialNumber != "2M2345678J" || jbod.Firmware != "4.34" {
+		t.Errorf("unexpected enclosure: %+v", jbod)
+	}
+	if len(jbod.Temperatures) != 2 || jbod.Temperatures["Temperature Sensor 1"] != 24 || jbod.Temperatures["Temperature Sensor 2"] != 31 {
+		t.Errorf("unexpected temperatures: %v", jbod.Temperatures)
+	}
+}
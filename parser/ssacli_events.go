@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SsacliEvents data structure for output
+type SsacliEvents struct {
+	SsacliEventData []SsacliEventData
+}
+
+// SsacliEventData is one entry of the controller event log
+type SsacliEventData struct {
+	// Seq is the event number printed by ssacli, 0 for logs that do not
+	// number their entries.
+	Seq         int64
+	Timestamp   string
+	Time        time.Time
+	Class       string
+	Severity    string
+	Description string
+}
+
+// eventTimeLayouts are the timestamp formats printed by the different
+// ssacli versions
+var eventTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"01/02/2006 15:04:05",
+	"Mon Jan 2 15:04:05 2006",
+	time.RFC3339,
+}
+
+// ParseSsacliEvents parses "ctrl slot=N show events"
+func ParseSsacliEvents(s string) *SsacliEvents {
+	var (
+		data []SsacliEventData
+		tmp  *SsacliEventData
+	)
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+
+		// Entries start with "Event: 24", "Event 24" or, on firmware that
+		// does not number them, a bare "Event:"
+		if rest, ok := strings.CutPrefix(line, "Event"); ok {
+			num := strings.TrimSpace(strings.TrimPrefix(rest, ":"))
+			seq, err := strconv.ParseInt(num, 10, 64)
+			if num == "" || err == nil {
+				data = append(data, SsacliEventData{Seq: seq})
+				tmp = &data[len(data)-1]
+				continue
+			}
+		}
+
+		if tmp == nil {
+			continue
+		}
+
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(kv[0])
+		val := strings.TrimSpace(kv[1])
+
+		switch key {
+		case "Timestamp", "Time", "Date/Time":
+			tmp.Timestamp = val
+			tmp.Time = parseEventTime(val)
+		case "Class", "Event Class":
+			tmp.Class = val
+		case "Severity":
+			tmp.Severity = val
+		case "Description", "Message", "Event Description":
+			tmp.Description = val
+		}
+	}
+
+	return &SsacliEvents{SsacliEventData: data}
+}
+
+// parseEventTime returns the zero time when the timestamp is in none of the
+// known layouts.
+func parseEventTime(s string) time.Time {
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSsacliEvents(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Event: 23
+      Timestamp: 2024-01-15 08:43:12
+      Class: Physical Drive
+      Severity: Warning
+      Description: Physical drive 1I:1:3 predictive failure
+
+   Event: 24
+      Timestamp: 01/16/2024 10:00:00
+      Class: Cache
+      Severity: Error
+      Description: Cache module failed
+`
+
+	data := ParseSsacliEvents(rawOutput).SsacliEventData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(data))
+	}
+
+	e := data[0]
+	if e.Seq != 23 || e.Class != "Physical Drive" || e.Severity != "Warning" || e.Description != "Physical drive 1I:1:3 predictive failure" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if want := time.Date(2024, 1, 15, 8, 43, 12, 0, time.Local); !e.Time.Equal(want) {
+		t.Errorf("Time: expected %v, got %v", want, e.Time)
+	}
+
+	if data[1].Seq != 24 || data[1].Time.IsZero() {
+		t.Errorf("unexpected second event: %+v", data[1])
+	}
+}
+
+func TestParseSsacliEventsUnnumbered(t *testing.T) {
+	rawOutput := `
+Smart Array P408i-a in Slot 0
+
+   Event:
+      Timestamp: 2024-01-15 08:43:12
+      Class: Physical Drive
+      Severity: Warning
+      Description: Physical drive 1I:1:3 predictive failure
+
+   Event:
+      Timestamp: 2024-01-16 10:00:00
+      Class: Cache
+      Severity: Error
+      Description: Cache module failed
+`
+
+	data := ParseSsacliEvents(rawOutput).SsacliEventData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(data))
+	}
+	for _, e := range data {
+		if e.Seq != 0 || e.Class == "" || e.Description == "" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	}
+}
@@ -10,6 +10,7 @@ type SsacliLogDisk struct {
 
 type SsacliLogDiskData struct {
 	ID             string
+	Array          string
 	Size           string
 	Cylinders      float64
 	Status         string
@@ -19,23 +20,61 @@ type SsacliLogDiskData struct {
 	LID            string
 	FaultTolerance string
 	UME            string
+	Acceleration   string
+	PhysDisks      []string
+	SizeBytes      *float64
+
+	// UnrecoverableMediaErrors is UME as a count, nil when not reported.
+	UnrecoverableMediaErrors *float64
+
+	StripSizeBytes      *float64
+	FullStripeSizeBytes *float64
+	DataStripeSizeBytes *float64
+	ParityGroups        *float64
+	Heads               *float64
+	SectorsPerTrack     *float64
+
+	// Device is LName as node_exporter names it, e.g. "sda" for
+	// "/dev/sda". MountPoints holds the paths mounted from it.
+	Device      string
+	MountPoints []string
+
+	// Progress of long running operations as a ratio between 0 and 1,
+	// nil when the operation is not running.
+	RebuildProgress    *float64
+	ParityInitStatus   string
+	ParityInitProgress *float64
+	TransformProgress  *float64
 }
 
+// rebuildStates and transformStates are the logical drive states ssacli
+// reports as "<state>, N% complete" while the operation is running.
+var (
+	rebuildStates   = []string{"Recovering", "Rebuilding"}
+	transformStates = []string{"Transforming", "Expanding", "Extending", "Moving"}
+)
+
 func ParseSsacliLogDisk(s string) *SsacliLogDisk {
 	var (
-		data []SsacliLogDiskData
-		tmp  SsacliLogDiskData
+		data  []SsacliLogDiskData
+		tmp   SsacliLogDiskData
+		array string
 	)
 
 	lines := strings.Split(s, "\n")
-	for i, line := range lines {
+	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
+		if name, ok := parseArrayHeader(line); ok {
+			array = name
+			continue
+		}
+
 		if strings.HasPrefix(line, "Logical Drive:") {
 			if tmp.ID != "" {
 				data = append(data, tmp)
 			}
-			tmp = SsacliLogDiskData{}
+			tmp = SsacliLogDiskData{Array: array}
 			parts := strings.Split(line, ": ")
 			if len(parts) > 1 {
 				tmp.ID = parts[1]
@@ -43,6 +82,15 @@ func ParseSsacliLogDisk(s string) *SsacliLogDisk {
 			continue
 		}
 
+		// Member drives are listed as
+		// "physicaldrive 1I:1:1 (port 1I:box 1:bay 1, SAS HDD, 600 GB, OK)"
+		if strings.HasPrefix(line, "physicaldrive ") {
+			if fields := strings.Fields(line); len(fields) > 1 {
+				tmp.PhysDisks = append(tmp.PhysDisks, fields[1])
+			}
+			continue
+		}
+
 		kv := strings.SplitN(line, ": ", 2)
 		if len(kv) == 2 {
 			key := strings.TrimSpace(kv[0])
@@ -51,33 +99,103 @@ func ParseSsacliLogDisk(s string) *SsacliLogDisk {
 			switch key {
 			case "Size":
 				tmp.Size = val
+				tmp.SizeBytes = parseSize(val, BinaryBase)
 			case "Cylinders":
-				tmp.Cylinders = toFLO(val)
+				// Not exported, a value that is not a number is left at 0
+				if v := parseSmartRawValue(val); v != nil {
+					tmp.Cylinders = *v
+				}
+			case "Heads":
+				tmp.Heads = parseSmartRawValue(val)
+			case "Sectors Per Track":
+				tmp.SectorsPerTrack = parseSmartRawValue(val)
+			case "Strip Size":
+				tmp.StripSizeBytes = parseSize(val, BinaryBase)
+			case "Full Stripe Size":
+				tmp.FullStripeSizeBytes = parseSize(val, BinaryBase)
+			case "Data Stripe Size":
+				tmp.DataStripeSizeBytes = parseSize(val, BinaryBase)
+			case "Number of Parity Groups":
+				tmp.ParityGroups = parseSmartRawValue(val)
 			case "Status":
-				tmp.Status = val
+				parseLogDiskStatus(&tmp, val)
+			case "Parity Initialization Status":
+				tmp.ParityInitStatus = val
+			case "Parity Initialization Progress":
+				tmp.ParityInitProgress = parsePercent(val)
 			case "Caching":
 				tmp.Caching = val
 			case "Unique Identifier":
 				tmp.UID = val
 			case "Disk Name":
 				tmp.LName = val
+				tmp.Device = strings.TrimPrefix(val, "/dev/")
+			case "Mount Points":
+				tmp.MountPoints = parseMountPoints(val)
 			case "Logical Drive Label":
 				tmp.LID = val
 			case "Fault Tolerance":
 				tmp.FaultTolerance = val
+			case "LD Acceleration Method":
+				tmp.Acceleration = val
 			case "Unrecoverable Media Errors":
 				tmp.UME = val
+				tmp.UnrecoverableMediaErrors = parseMediaErrors(val)
 			}
 		}
-
-		if i == len(lines)-1 && tmp.ID != "" {
-			data = append(data, tmp)
-		}
 	}
 
-	if len(data) == 0 && tmp.Status != "" {
+	if tmp.ID != "" || (len(data) == 0 && tmp.Status != "") {
 		data = append(data, tmp)
 	}
 
 	return &SsacliLogDisk{SsacliLogDiskData: data}
 }
+
+// parseLogDiskStatus splits "Recovering, 43% complete" into the state and
+// the progress of the operation it names.
+func parseLogDiskStatus(d *SsacliLogDiskData, s string) {
+	state, progress, _ := strings.Cut(s, ", ")
+	if !strings.HasSuffix(progress, "% complete") {
+		d.Status = s
+		return
+	}
+	d.Status = state
+
+	for _, st := range rebuildStates {
+		if state == st {
+			d.RebuildProgress = parsePercent(progress)
+		}
+	}
+	for _, st := range transformStates {
+		if state == st {
+			d.TransformProgress = parsePercent(progress)
+		}
+	}
+}
+
+// parseMountPoints extracts the paths from
+// "/boot 256 MB Partition Number 1, / 100 GB Partition Number 2"
+func parseMountPoints(s string) []string {
+	if s == "None" {
+		return nil
+	}
+
+	var mounts []string
+	for _, part := range strings.Split(s, ", ") {
+		if fields := strings.Fields(part); len(fields) > 0 {
+			mounts = append(mounts, fields[0])
+		}
+	}
+	return mounts
+}
+
+// parseMediaErrors converts "Unrecoverable Media Errors", which ssacli
+// prints as "None" when there are none, into a count.
+func parseMediaErrors(s string) *float64 {
+	if s == "None" {
+		val := 0.0
+		return &val
+	}
+	return parseSmartRawValue(s)
+}
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliLogDiskProgress(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      Logical Drive: 1
+         Size: 558.9 GB
+         Fault Tolerance: 1
+         Status: Recovering, 43% complete
+         Unrecoverable Media Errors: None
+
+   Array B
+
+      Logical Drive: 2
+         Size: 1.1 TB
+         Fault Tolerance: 5
+         Status: OK
+         Parity Initialization Status: In Progress
+         Parity Initialization Progress: 12% complete
+`
+
+	data := ParseSsacliLogDisk(rawOutput).SsacliLogDiskData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 logical drives, got %d", len(data))
+	}
+
+	// 1. Progress is split off the status
+	if data[0].Status != "Recovering" || data[0].Array != "A" {
+		t.Errorf("unexpected LD 1: status %q, array %q", data[0].Status, data[0].Array)
+	}
+	if data[0].RebuildProgress == nil || *data[0].RebuildProgress != 0.43 {
+		t.Errorf("RebuildProgress: expected 0.43, got %v", data[0].RebuildProgress)
+	}
+	if data[0].TransformProgress != nil || data[0].ParityInitProgress != nil {
+		t.Errorf("LD 1 should only report rebuild progress")
+	}
+
+	// 2. Parity initialization is reported on its own lines
+	if data[1].ParityInitStatus != "In Progress" || data[1].ParityInitProgress == nil || *data[1].ParityInitProgress != 0.12 {
+		t.Errorf("unexpected parity initialization for LD 2: %q %v", data[1].ParityInitStatus, data[1].ParityInitProgress)
+	}
+	if data[1].RebuildProgress != nil {
+		t.Errorf("LD 2 should not report rebuild progress")
+	}
+}
+
+func TestParseSsacliLogDiskDevice(t *testing.T) {
+	rawOutput := `
+   Array A
+
+      Logical Drive: 1
+         Size: 558.9 GB
+         Status: OK
+         Unique Identifier: 600508B1001C6D2A2E4F3B8E6A1C0F2D
+         Disk Name: /dev/sda
+         Mount Points: /boot 256 MB Partition Number 1, / 558.6 GB Partition Number 2
+         Logical Drive Label: 01A2B3C4PDVTF0ARH4D0A1B2
+         LD Acceleration Method: HPE SSD Smart Path
+
+      Logical Drive: 2
+         Size: 1.1 TB
+         Status: OK
+         Disk Name: /dev/sdb
+         Mount Points: None
+`
+
+	data := ParseSsacliLogDisk(rawOutput).SsacliLogDiskData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 logical drives, got %d", len(data))
+	}
+
+	if data[0].Device != "sda" || data[0].UID != "600508B1001C6D2A2E4F3B8E6A1C0F2D" || data[0].LID != "01A2B3C4PDVTF0ARH4D0A1B2" {
+		t.Errorf("unexpected LD 1: device %q, uid %q, label %q", data[0].Device, data[0].UID, data[0].LID)
+	}
+	if len(data[0].MountPoints) != 2 || data[0].MountPoints[0] != "/boot" || data[0].MountPoints[1] != "/" {
+		t.Errorf("unexpected mount points for LD 1: %v", data[0].MountPoints)
+	}
+	if data[0].Acceleration != "HPE SSD Smart Path" || data[1].Acceleration != "" {
+		t.Errorf("unexpected acceleration: %q, %q", data[0].Acceleration, data[1].Acceleration)
+	}
+	if data[1].Device != "sdb" || data[1].MountPoints != nil {
+		t.Errorf("unexpected LD 2: device %q, mount points %v", data[1].Device, data[1].MountPoints)
+	}
+}
+
+func TestParseSsacliLogDiskGeometry(t *testing.T) {
+	rawOutput := `
+   Array A
+
+      Logical Drive: 1
+         Size: 3.3 TB
+         Fault Tolerance: 50
+         Number of Parity Groups: 2
+         Heads: 255
+         Sectors Per Track: 32
+         Cylinders: 65535
+         Strip Size: 256 KB
+         Full Stripe Size: 1024 KB
+         Status: OK
+
+      Logical Drive: 2
+         Size: 558.9 GB
+         Fault Tolerance: 1
+         Cylinders: N/A
+         Strip Size: 128 KB
+         Data Stripe Size: 128 KB
+         Status: OK
+`
+
+	data := ParseSsacliLogDisk(rawOutput).SsacliLogDiskData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 logical drives, got %d", len(data))
+	}
+
+	ld := data[0]
+	if ld.StripSizeBytes == nil || *ld.StripSizeBytes != 256*1024 {
+		t.Errorf("StripSizeBytes: expected %d, got %v", 256*1024, ld.StripSizeBytes)
+	}
+	if ld.FullStripeSizeBytes == nil || *ld.FullStripeSizeBytes != 1024*1024 {
+		t.Errorf("FullStripeSizeBytes: expected %d, got %v", 1024*1024, ld.FullStripeSizeBytes)
+	}
+	if ld.ParityGroups == nil || *ld.ParityGroups != 2 || ld.Heads == nil || *ld.Heads != 255 || ld.SectorsPerTrack == nil || *ld.SectorsPerTrack != 32 {
+		t.Errorf("unexpected geometry: parity groups %v, heads %v, sectors %v", ld.ParityGroups, ld.Heads, ld.SectorsPerTrack)
+	}
+
+	// Older ssacli prints the full stripe as "Data Stripe Size"
+	if data[1].FullStripeSizeBytes != nil || data[1].DataStripeSizeBytes == nil || *data[1].DataStripeSizeBytes != 128*1024 {
+		t.Errorf("unexpected stripe for LD 2: full %v, data %v", data[1].FullStripeSizeBytes, data[1].DataStripeSizeBytes)
+	}
+	if data[1].ParityGroups != nil {
+		t.Errorf("LD 2 should not report parity groups")
+	}
+
+	// A cylinder count that is not a number does not stop the exporter
+	if ld.Cylinders != 65535 || data[1].Cylinders != 0 {
+		t.Errorf("unexpected cylinders: %v, %v", ld.Cylinders, data[1].Cylinders)
+	}
+}
+
+func TestParseSsacliLogDiskMediaErrors(t *testing.T) {
+	rawOutput := `
+      Logical Drive: 1
+         Status: OK
+         Unrecoverable Media Errors: None
+
+      Logical Drive: 2
+         Status: OK
+         Unrecoverable Media Errors: 3
+
+      Logical Drive: 3
+         Status: OK
+`
+
+	data := ParseSsacliLogDisk(rawOutput).SsacliLogDiskData
+	if len(data) != 3 {
+		t.Fatalf("expected 3 logical drives, got %d", len(data))
+	}
+
+	if data[0].UnrecoverableMediaErrors == nil || *data[0].UnrecoverableMediaErrors != 0 {
+		t.Errorf("LD 1: expected 0 media errors, got %v", data[0].UnrecoverableMediaErrors)
+	}
+	if data[1].UnrecoverableMediaErrors == nil || *data[1].UnrecoverableMediaErrors != 3 {
+		t.Errorf("LD 2: expected 3 media errors, got %v", data[1].UnrecoverableMediaErrors)
+	}
+	if data[2].UnrecoverableMediaErrors != nil {
+		t.Errorf("LD 3: expected no media error count, got %v", *data[2].UnrecoverableMediaErrors)
+	}
+}
+
+func TestParseLogDiskStatus(t *testing.T) {
+	ratio := func(v float64) *float64 { return &v }
+
+	for _, tc := range []struct {
+		in        string
+		status    string
+		rebuild   *float64
+		transform *float64
+	}{
+		{"Recovering, 43% complete", "Recovering", ratio(0.43), nil},
+		{"Rebuilding, 0% complete", "Rebuilding", ratio(0), nil},
+		{"Transforming, 99.5% complete", "Transforming", nil, ratio(0.995)},
+		{"OK", "OK", nil, nil},
+		{"Interim Recovery Mode", "Interim Recovery Mode", nil, nil},
+		// Malformed percentages keep the state but report no progress
+		{"Recovering, abc% complete", "Recovering", nil, nil},
+		{"Recovering, % complete", "Recovering", nil, nil},
+		{"Recovering, 43 percent", "Recovering, 43 percent", nil, nil},
+	} {
+		var d SsacliLogDiskData
+		parseLogDiskStatus(&d, tc.in)
+
+		if d.Status != tc.status {
+			t.Errorf("%q: expected status %q, got %q", tc.in, tc.status, d.Status)
+		}
+		for _, p := range []struct {
+			name      string
+			got, want *float64
+		}{
+			{"RebuildProgress", d.RebuildProgress, tc.rebuild},
+			{"TransformProgress", d.TransformProgress, tc.transform},
+		} {
+			if (p.got == nil) != (p.want == nil) || (p.got != nil && *p.got != *p.want) {
+				t.Errorf("%q: %s expected %v, got %v", tc.in, p.name, deref(p.want), deref(p.got))
+			}
+		}
+	}
+}
+
+func deref(f *float64) any {
+	if f == nil {
+		return nil
+	}
+	return *f
+}
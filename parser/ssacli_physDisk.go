@@ -13,6 +13,7 @@ type SsacliPhysDisk struct {
 // SsacliPhysDiskData data structure for output
 type SsacliPhysDiskData struct {
 	ID        string
+	Array     string
 	Bay       string
 	Status    string
 	DriveType string
@@ -23,9 +24,31 @@ type SsacliPhysDiskData struct {
 	Firmware  string
 	SN        string
 	WWID      string
-	CurTemp   float64
-	MaxTemp   float64
-	Model     string
+	// CurTemp and MaxTemp are in degrees Celsius, nil when the drive does
+	// not report them.
+	CurTemp *float64
+	MaxTemp *float64
+	Model   string
+	PHYRate string
+
+	SizeBytes         *float64
+	LogicalBlockSize  *float64
+	PhysicalBlockSize *float64
+	// RotationalSpeed is 0 for solid state drives
+	RotationalSpeed *float64
+
+	// Link rates in Gbps with one entry per PHY, 0 for PHYs without a
+	// link
+	PHYCount             int
+	PHYTransferRates     []float64
+	PHYPhysicalLinkRates []float64
+	PHYMaximumLinkRates  []float64
+
+	// SSD endurance, nil for drives that do not report it
+	UsageRemaining         *float64
+	PowerOnHours           *float64
+	EstimatedLifeRemaining *float64
+	SmartTripWearout       *float64
 }
 
 // ParseSsacliPhysDisk return specific metric
@@ -37,19 +60,25 @@ func parseSsacliPhysDisk(s string) *SsacliPhysDisk {
 	var (
 		disks []SsacliPhysDiskData
 		tmp   SsacliPhysDiskData
+		array string
 	)
 
 	re := regexp.MustCompile(`(.+?)\: (.+)`)
 	lines := strings.Split(s, "\n")
 
-	for i, line := range lines {
+	for _, line := range lines {
 		kvs := strings.Trim(line, " \t\r")
 
+		if name, ok := parseArrayHeader(kvs); ok {
+			array = name
+			continue
+		}
+
 		if strings.HasPrefix(kvs, "physicaldrive ") {
 			if tmp.ID != "" {
 				disks = append(disks, tmp)
 			}
-			tmp = SsacliPhysDiskData{}
+			tmp = SsacliPhysDiskData{Array: array}
 			parts := strings.Split(kvs, " ")
 			if len(parts) > 1 {
 				tmp.ID = parts[1]
@@ -74,10 +103,16 @@ func parseSsacliPhysDisk(s string) *SsacliPhysDisk {
 				tmp.IntType = value
 			case "Size":
 				tmp.Size = value
+				tmp.SizeBytes = parseSize(value, DecimalBase)
 			case "Logical/Physical Block Size":
 				tmp.BlockSize = value
+				if logical, physical, ok := strings.Cut(value, "/"); ok {
+					tmp.LogicalBlockSize = parseSmartRawValue(logical)
+					tmp.PhysicalBlockSize = parseSmartRawValue(physical)
+				}
 			case "Rotational Speed":
 				tmp.Speed = value
+				tmp.RotationalSpeed = parseRotationalSpeed(value)
 			case "Firmware Revision":
 				tmp.Firmware = value
 			case "WWID":
@@ -85,20 +120,81 @@ func parseSsacliPhysDisk(s string) *SsacliPhysDisk {
 			case "Model":
 				tmp.Model = value
 			case "Current Temperature (C)":
-				tmp.CurTemp = toFLO(value)
+				tmp.CurTemp = parseSmartRawValue(value)
 			case "Maximum Temperature (C)":
-				tmp.MaxTemp = toFLO(value)
+				tmp.MaxTemp = parseSmartRawValue(value)
+			case "PHY Count":
+				if n := parseSmartRawValue(value); n != nil {
+					tmp.PHYCount = int(*n)
+				}
+			case "PHY Transfer Rate":
+				tmp.PHYRate = value
+				tmp.PHYTransferRates = parseLinkRates(value)
+			case "PHY Physical Link Rate":
+				tmp.PHYPhysicalLinkRates = parseLinkRates(value)
+			case "PHY Maximum Link Rate":
+				tmp.PHYMaximumLinkRates = parseLinkRates(value)
+			case "Usage remaining":
+				tmp.UsageRemaining = parsePercent(value)
+			case "Power On Hours":
+				tmp.PowerOnHours = parseSmartRawValue(value)
+			case "Estimated Life Remaining based on workload to date":
+				tmp.EstimatedLifeRemaining = parseSmartRawValue(strings.TrimSuffix(value, " days"))
+			case "SSD Smart Trip Wearout":
+				tmp.SmartTripWearout = parseBool(value)
 			}
 		}
-
-		if i == len(lines)-1 && tmp.ID != "" {
-			disks = append(disks, tmp)
-		}
 	}
 
-	if len(disks) == 0 && tmp.Status != "" {
+	if tmp.ID != "" || (len(disks) == 0 && tmp.Status != "") {
 		disks = append(disks, tmp)
 	}
 
 	return &SsacliPhysDisk{SsacliPhysDiskData: disks}
 }
+
+// parseRotationalSpeed converts "10000" or "7200 RPM" into rpm, and the
+// "Solid State" ssacli prints for SSDs into 0.
+func parseRotationalSpeed(s string) *float64 {
+	if strings.EqualFold(s, "Solid State") {
+		zero := 0.0
+		return &zero
+	}
+	return parseSmartRawValue(strings.TrimSuffix(s, " RPM"))
+}
+
+// parseLinkRates converts per-PHY rates such as "6.0Gbps, Unknown" into
+// Gbps, with 0 for PHYs ssacli reports as "Unknown".
+func parseLinkRates(s string) []float64 {
+	var rates []float64
+	for _, r := range strings.Split(s, ",") {
+		rate, err := ParseGbps(r)
+		if err != nil {
+			rate = 0
+		}
+		rates = append(rates, rate)
+	}
+	return rates
+}
+
+// Physical drive roles as returned by SsacliPhysDiskData.Role
+const (
+	RoleData       = "data"
+	RoleSpare      = "spare"
+	RoleUnassigned = "unassigned"
+)
+
+// Role reports whether the drive holds data of an array, stands by as a
+// spare or is not used at all. ssacli prints it as "Drive Type" ("Data
+// Drive", "Spare Drive", "Unassigned Drive"); older versions that omit it
+// fall back to the array the drive was listed under.
+func (d SsacliPhysDiskData) Role() string {
+	switch {
+	case strings.Contains(d.DriveType, "Spare"):
+		return RoleSpare
+	case strings.Contains(d.DriveType, "Unassigned"), d.Array == "":
+		return RoleUnassigned
+	default:
+		return RoleData
+	}
+}
@@ -0,0 +1,155 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliPhysDiskGeometry(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      physicaldrive 1I:1:1
+         Status: OK
+         Drive Type: Data Drive
+         Interface Type: SAS
+         Size: 1.2 TB
+         Logical/Physical Block Size: 512/4096
+         Rotational Speed: 10000
+         PHY Count: 2
+         PHY Transfer Rate: 6.0Gbps, Unknown
+         PHY Maximum Link Rate: 12.0Gbps, 12.0Gbps
+
+   Unassigned
+
+      physicaldrive 1I:1:2
+         Status: OK
+         Drive Type: Unassigned Drive
+         Interface Type: Solid State SATA
+         Size: 480 GB
+         Rotational Speed: Solid State
+         Usage remaining: 99.74%
+         Power On Hours: 12345
+         Estimated Life Remaining based on workload to date: 8561 days
+         SSD Smart Trip Wearout: False
+`
+
+	data := ParseSsacliPhysDisk(rawOutput).SsacliPhysDiskData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 physical drives, got %d", len(data))
+	}
+
+	// 1. Decimal capacity, split block sizes and rpm
+	hdd := data[0]
+	if hdd.SizeBytes == nil || *hdd.SizeBytes != 1.2e12 {
+		t.Errorf("SizeBytes: expected 1.2e12, got %v", hdd.SizeBytes)
+	}
+	if hdd.LogicalBlockSize == nil || *hdd.LogicalBlockSize != 512 {
+		t.Errorf("LogicalBlockSize: expected 512, got %v", hdd.LogicalBlockSize)
+	}
+	if hdd.PhysicalBlockSize == nil || *hdd.PhysicalBlockSize != 4096 {
+		t.Errorf("PhysicalBlockSize: expected 4096, got %v", hdd.PhysicalBlockSize)
+	}
+	if hdd.RotationalSpeed == nil || *hdd.RotationalSpeed != 10000 {
+		t.Errorf("RotationalSpeed: expected 10000, got %v", hdd.RotationalSpeed)
+	}
+
+	// 2. Per-PHY link rates, 0 for PHYs without a link
+	if hdd.PHYCount != 2 || len(hdd.PHYTransferRates) != 2 || hdd.PHYTransferRates[0] != 6 || hdd.PHYTransferRates[1] != 0 {
+		t.Errorf("unexpected PHY transfer rates: %d %v", hdd.PHYCount, hdd.PHYTransferRates)
+	}
+	if len(hdd.PHYMaximumLinkRates) != 2 || hdd.PHYMaximumLinkRates[1] != 12 {
+		t.Errorf("unexpected PHY maximum link rates: %v", hdd.PHYMaximumLinkRates)
+	}
+
+	// 3. SSDs report 0 rpm and nothing for missing block sizes
+	ssd := data[1]
+	if ssd.Array != "" {
+		t.Errorf("unassigned drive reported in array %q", ssd.Array)
+	}
+	if ssd.RotationalSpeed == nil || *ssd.RotationalSpeed != 0 {
+		t.Errorf("RotationalSpeed: expected 0, got %v", ssd.RotationalSpeed)
+	}
+	if ssd.LogicalBlockSize != nil {
+		t.Errorf("LogicalBlockSize should be nil, got %v", *ssd.LogicalBlockSize)
+	}
+
+	// 4. SSD endurance, absent on hard disks
+	if ssd.UsageRemaining == nil || *ssd.UsageRemaining != 0.9974 {
+		t.Errorf("UsageRemaining: expected 0.9974, got %v", ssd.UsageRemaining)
+	}
+	if ssd.PowerOnHours == nil || *ssd.PowerOnHours != 12345 {
+		t.Errorf("PowerOnHours: expected 12345, got %v", ssd.PowerOnHours)
+	}
+	if ssd.EstimatedLifeRemaining == nil || *ssd.EstimatedLifeRemaining != 8561 {
+		t.Errorf("EstimatedLifeRemaining: expected 8561, got %v", ssd.EstimatedLifeRemaining)
+	}
+	if ssd.SmartTripWearout == nil || *ssd.SmartTripWearout != 0 {
+		t.Errorf("SmartTripWearout: expected 0, got %v", ssd.SmartTripWearout)
+	}
+	if hdd.UsageRemaining != nil || hdd.SmartTripWearout != nil {
+		t.Errorf("hard disk should not report SSD endurance")
+	}
+}
+
+func TestParseSsacliPhysDiskRole(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      physicaldrive 1I:1:1
+         Status: OK
+         Drive Type: Data Drive
+
+      physicaldrive 1I:1:4
+         Status: OK
+         Drive Type: Spare Drive
+
+   Unassigned
+
+      physicaldrive 2I:1:5
+         Status: OK
+         Drive Type: Unassigned Drive
+
+      physicaldrive 2I:1:6
+         Status: OK
+`
+
+	data := ParseSsacliPhysDisk(rawOutput).SsacliPhysDiskData
+	want := []string{RoleData, RoleSpare, RoleUnassigned, RoleUnassigned}
+	if len(data) != len(want) {
+		t.Fatalf("expected %d drives, got %d", len(want), len(data))
+	}
+	for i, role := range want {
+		if got := data[i].Role(); got != role {
+			t.Errorf("%s: expected role %q, got %q", data[i].ID, role, got)
+		}
+	}
+}
+
+func TestParseSsacliPhysDiskTemperature(t *testing.T) {
+	rawOutput := `
+      physicaldrive 1I:1:1
+         Status: OK
+         Current Temperature (C): 31
+         Maximum Temperature (C): 44
+
+      physicaldrive 1I:1:2
+         Status: Failed
+`
+
+	data := ParseSsacliPhysDisk(rawOutput).SsacliPhysDiskData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 physical drives, got %d", len(data))
+	}
+
+	if data[0].CurTemp == nil || *data[0].CurTemp != 31 || data[0].MaxTemp == nil || *data[0].MaxTemp != 44 {
+		t.Errorf("unexpected temperatures for 1I:1:1: %v, %v", data[0].CurTemp, data[0].MaxTemp)
+	}
+	// A drive that does not report its temperature has none, not 0
+	if data[1].CurTemp != nil || data[1].MaxTemp != nil {
+		t.Errorf("1I:1:2 should report no temperature, got %v, %v", data[1].CurTemp, data[1].MaxTemp)
+	}
+}
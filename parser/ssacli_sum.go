@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -8,24 +11,61 @@ import (
 type SsacliSum struct {
 	ContNumber    int
 	SsacliSumData []SsacliSumData
+
+	// Err lists the values that could not be parsed. The fields they
+	// belong to are left unset.
+	Err error
 }
 
 // SsacliSumData data structure for output
 type SsacliSumData struct {
-	Slot           int64
-	SlotID         string
-	SerialNumber   string
-	ContStatus     string
-	FirmVersion    string
-	TotalCacheSize float64
-	AvailCacheSize float64
-	BatteryStatus  string
-	ContTemp       float64
-	CahceModuTemp  float64
-	BatteryTemp    float64
-	Encryption     string
-	DriverName     string
-	DriverVersion  string
+	Model         string
+	Slot          int64
+	SlotID        string
+	SerialNumber  string
+	ContStatus    string
+	FirmVersion   string
+	CacheStatus   string
+	BatteryStatus string
+
+	// Cache sizes in GB, nil when not reported.
+	TotalCacheSize *float64
+	AvailCacheSize *float64
+
+	CacheReadRatio         *float64
+	CacheWriteRatio        *float64
+	CacheBoardPresent      *float64
+	NoBatteryWriteCache    *float64
+	DriveWriteCache        string
+	CacheBackupPowerSource string
+
+	RebuildPriority                 string
+	ExpandPriority                  string
+	SurfaceScanDelay                *float64
+	SurfaceScanMode                 string
+	ParallelSurfaceScanSupported    *float64
+	ParallelSurfaceScanCount        *float64
+	MaxParallelSurfaceScanCount     *float64
+	QueueDepth                      string
+	ElevatorSort                    string
+	DegradedPerformanceOptimization string
+
+	// QueueDepthCount is nil when the queue depth is "Automatic".
+	QueueDepthCount                        *float64
+	ElevatorSortEnabled                    *float64
+	DegradedPerformanceOptimizationEnabled *float64
+
+	ControllerMode      string
+	SpareActivationMode string
+	SmartPath           string
+
+	// Temperatures in degrees Celsius, nil when not reported.
+	ContTemp      *float64
+	CahceModuTemp *float64
+	BatteryTemp   *float64
+	Encryption    string
+	DriverName    string
+	DriverVersion string
 }
 
 // ParseSsacliSum return specific metric
@@ -38,21 +78,61 @@ func ParseSsacliSum(s string) *SsacliSum {
 func parseSmartAttrs(s string) *SsacliSum {
 
 	var (
-		contNumber int
-		tmp        SsacliSumData
-		// tmp1 ssacliSumData
+		ctrls []SsacliSumData
+		tmp   SsacliSumData
+		errs  []error
 	)
 
+	temperature := func(key string, val string) *float64 {
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("slot %s: invalid %s %q", tmp.SlotID, key, val))
+			return nil
+		}
+		return &f
+	}
+
+	// ssacli prints cache sizes in GB without a unit, hpssacli and
+	// hpacucli as "1024 MB"
+	cacheSize := func(key string, val string) *float64 {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return &f
+		}
+		b, err := ParseBytes(val, 1024)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("slot %s: invalid %s %q", tmp.SlotID, key, val))
+			return nil
+		}
+		gb := b / (1 << 30)
+		return &gb
+	}
+
 	for _, line := range strings.Split(s, "\n") {
 		kvs := strings.Trim(line, " \t")
+
+		// Every controller section starts with a header such as
+		// "Smart Array P440ar in Slot 0 (Embedded)"
+		if idx := strings.Index(kvs, " in Slot "); idx != -1 && !strings.Contains(kvs, ": ") {
+			if tmp.SlotID != "" {
+				ctrls = append(ctrls, tmp)
+			}
+			tmp = SsacliSumData{Model: kvs[:idx]}
+			continue
+		}
+
 		kv := strings.Split(kvs, ": ")
 
 		if len(kv) == 2 {
 
-			switch kv[0] {
+			key := strings.Join(strings.Fields(kv[0]), " ")
+			switch key {
 			case "Slot":
-				tmp.Slot = toINT(kv[1])
 				tmp.SlotID = kv[1]
+				slot, err := toINT(kv[1])
+				if err != nil {
+					errs = append(errs, fmt.Errorf("slot %s: invalid %s %q", kv[1], key, kv[1]))
+				}
+				tmp.Slot = slot
 			case "Serial Number":
 				tmp.SerialNumber = kv[1]
 			case "Controller Status":
@@ -60,17 +140,58 @@ func parseSmartAttrs(s string) *SsacliSum {
 			case "Firmware Version":
 				tmp.FirmVersion = kv[1]
 			case "Total Cache Size":
-				tmp.TotalCacheSize = toFLO(kv[1])
+				tmp.TotalCacheSize = cacheSize(key, kv[1])
 			case "Total Cache Memory Available":
-				tmp.AvailCacheSize = toFLO(kv[1])
-			case "Battery/Capacitor Status":
+				tmp.AvailCacheSize = cacheSize(key, kv[1])
+			case "Cache Status":
+				tmp.CacheStatus = kv[1]
+			case "Cache Ratio":
+				tmp.CacheReadRatio, tmp.CacheWriteRatio = parseCacheRatio(kv[1])
+			case "Cache Board Present":
+				tmp.CacheBoardPresent = parseBool(kv[1])
+			case "Drive Write Cache":
+				tmp.DriveWriteCache = kv[1]
+			case "No-Battery Write Cache":
+				tmp.NoBatteryWriteCache = parseBool(kv[1])
+			case "Cache Backup Power Source":
+				tmp.CacheBackupPowerSource = kv[1]
+			case "Battery/Capacitor Status", "Battery Status":
 				tmp.BatteryStatus = kv[1]
 			case "Controller Temperature (C)":
-				tmp.ContTemp = toFLO(kv[1])
+				tmp.ContTemp = temperature(key, kv[1])
 			case "Cache Module Temperature (C)":
-				tmp.CahceModuTemp = toFLO(kv[1])
-			case "Capacitor Temperature  (C)":
-				tmp.BatteryTemp = toFLO(kv[1])
+				tmp.CahceModuTemp = temperature(key, kv[1])
+			case "Capacitor Temperature (C)", "Battery Temperature (C)", "Battery/Capacitor Temperature (C)":
+				tmp.BatteryTemp = temperature(key, kv[1])
+			case "Rebuild Priority":
+				tmp.RebuildPriority = kv[1]
+			case "Expand Priority":
+				tmp.ExpandPriority = kv[1]
+			case "Surface Scan Delay":
+				tmp.SurfaceScanDelay = parseSeconds(kv[1])
+			case "Surface Scan Mode":
+				tmp.SurfaceScanMode = kv[1]
+			case "Parallel Surface Scan Supported":
+				tmp.ParallelSurfaceScanSupported = parseBool(kv[1])
+			case "Current Parallel Surface Scan Count", "Parallel Surface Scan Count":
+				tmp.ParallelSurfaceScanCount = parseSmartRawValue(kv[1])
+			case "Max Parallel Surface Scan Count":
+				tmp.MaxParallelSurfaceScanCount = parseSmartRawValue(kv[1])
+			case "Queue Depth":
+				tmp.QueueDepth = kv[1]
+				tmp.QueueDepthCount = parseSmartRawValue(kv[1])
+			case "Elevator Sort":
+				tmp.ElevatorSort = kv[1]
+				tmp.ElevatorSortEnabled = parseBool(kv[1])
+			case "Degraded Performance Optimization":
+				tmp.DegradedPerformanceOptimization = kv[1]
+				tmp.DegradedPerformanceOptimizationEnabled = parseBool(kv[1])
+			case "Controller Mode":
+				tmp.ControllerMode = kv[1]
+			case "Spare Activation Mode":
+				tmp.SpareActivationMode = kv[1]
+			case "HPE SSD Smart Path", "HP SSD Smart Path", "Smart Path":
+				tmp.SmartPath = kv[1]
 			case "Encryption":
 				tmp.Encryption = kv[1]
 			case "Driver Name":
@@ -112,12 +233,37 @@ func parseSmartAttrs(s string) *SsacliSum {
 		}
 	}
 
+	if tmp.SlotID != "" || len(ctrls) == 0 {
+		ctrls = append(ctrls, tmp)
+	}
+
 	data := SsacliSum{
-		ContNumber: contNumber,
-		SsacliSumData: []SsacliSumData{
-			tmp,
-			// tmp1,
-		},
+		ContNumber:    len(ctrls),
+		SsacliSumData: ctrls,
+		Err:           errors.Join(errs...),
 	}
 	return &data
 }
+
+// parseCacheRatio splits "10% Read / 90% Write" into read and write ratios.
+func parseCacheRatio(s string) (read *float64, write *float64) {
+	for _, part := range strings.Split(s, "/") {
+		part = strings.TrimSpace(part)
+		switch {
+		case strings.HasSuffix(part, "Read"):
+			read = parsePercent(part)
+		case strings.HasSuffix(part, "Write"):
+			write = parsePercent(part)
+		}
+	}
+	return read, write
+}
+
+// parseSeconds converts durations such as "3 secs" into seconds.
+func parseSeconds(s string) *float64 {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil
+	}
+	return parseSmartRawValue(fields[0])
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliSumCache(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   Controller Status: OK
+   Cache Board Present: True
+   Cache Status: Temporarily Disabled
+   Cache Ratio: 10% Read / 90% Write
+   Drive Write Cache: Disabled
+   Total Cache Size: 2.0
+   No-Battery Write Cache: Disabled
+   Cache Backup Power Source: Batteries
+   Battery/Capacitor Status: Failed (Replace Batteries/Capacitors)
+
+Smart Array P420i in Slot 3
+   Slot: 3
+   Controller Status: OK
+   Cache Board Present: False
+`
+
+	data := ParseSsacliSum(rawOutput).SsacliSumData
+	if len(data) != 2 {
+		t.Fatalf("expected 2 controllers, got %d", len(data))
+	}
+
+	c := data[0]
+	if c.CacheReadRatio == nil || *c.CacheReadRatio != 0.1 || c.CacheWriteRatio == nil || *c.CacheWriteRatio != 0.9 {
+		t.Errorf("unexpected cache ratio: %v / %v", c.CacheReadRatio, c.CacheWriteRatio)
+	}
+	if c.CacheBoardPresent == nil || *c.CacheBoardPresent != 1 {
+		t.Errorf("CacheBoardPresent: expected 1, got %v", c.CacheBoardPresent)
+	}
+	if c.NoBatteryWriteCache == nil || *c.NoBatteryWriteCache != 0 {
+		t.Errorf("NoBatteryWriteCache: expected 0, got %v", c.NoBatteryWriteCache)
+	}
+	if c.CacheStatus != "Temporarily Disabled" || c.DriveWriteCache != "Disabled" || c.CacheBackupPowerSource != "Batteries" {
+		t.Errorf("unexpected cache policy: %+v", c)
+	}
+
+	// A controller without a cache module reports no ratio
+	if data[1].CacheBoardPresent == nil || *data[1].CacheBoardPresent != 0 || data[1].CacheReadRatio != nil {
+		t.Errorf("unexpected cache for slot 3: %v %v", data[1].CacheBoardPresent, data[1].CacheReadRatio)
+	}
+}
+
+func TestParseSsacliSumSettings(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   Rebuild Priority: High
+   Expand Priority: Medium
+   Surface Scan Delay: 3 secs
+   Surface Scan Mode: Idle
+   Parallel Surface Scan Supported: Yes
+   Current Parallel Surface Scan Count: 1
+   Max Parallel Surface Scan Count: 16
+   Queue Depth: Automatic
+   Elevator Sort: Enabled
+   Degraded Performance Optimization: Disabled
+   Controller Mode: Mixed
+`
+
+	c := ParseSsacliSum(rawOutput).SsacliSumData[0]
+	if c.RebuildPriority != "High" || c.ExpandPriority != "Medium" || c.SurfaceScanMode != "Idle" || c.QueueDepth != "Automatic" ||
+		c.ElevatorSort != "Enabled" || c.DegradedPerformanceOptimization != "Disabled" || c.ControllerMode != "Mixed" {
+		t.Errorf("unexpected settings: %+v", c)
+	}
+	if c.SurfaceScanDelay == nil || *c.SurfaceScanDelay != 3 {
+		t.Errorf("SurfaceScanDelay: expected 3, got %v", c.SurfaceScanDelay)
+	}
+	if c.ParallelSurfaceScanSupported == nil || *c.ParallelSurfaceScanSupported != 1 {
+		t.Errorf("ParallelSurfaceScanSupported: expected 1, got %v", c.ParallelSurfaceScanSupported)
+	}
+	if c.ParallelSurfaceScanCount == nil || *c.ParallelSurfaceScanCount != 1 || c.MaxParallelSurfaceScanCount == nil || *c.MaxParallelSurfaceScanCount != 16 {
+		t.Errorf("unexpected parallel surface scan count: %v / %v", c.ParallelSurfaceScanCount, c.MaxParallelSurfaceScanCount)
+	}
+	if c.ElevatorSortEnabled == nil || *c.ElevatorSortEnabled != 1 || c.DegradedPerformanceOptimizationEnabled == nil || *c.DegradedPerformanceOptimizationEnabled != 0 {
+		t.Errorf("unexpected switches: elevator sort %v, degraded performance optimization %v", c.ElevatorSortEnabled, c.DegradedPerformanceOptimizationEnabled)
+	}
+	// An automatic queue depth has no count
+	if c.QueueDepthCount != nil {
+		t.Errorf("QueueDepthCount: expected none, got %v", *c.QueueDepthCount)
+	}
+
+	c = ParseSsacliSum("   Slot: 1\n   Queue Depth: 32\n").SsacliSumData[0]
+	if c.QueueDepthCount == nil || *c.QueueDepthCount != 32 {
+		t.Errorf("QueueDepthCount: expected 32, got %v", c.QueueDepthCount)
+	}
+}
+
+func TestParseSsacliSumTemperatures(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   Controller Temperature (C): 49
+   Cache Module Temperature (C): 37
+   Capacitor Temperature  (C): 31
+
+Smart Array P420i in Slot 3
+   Slot: 3
+   Controller Temperature (C): N/A
+`
+
+	sum := ParseSsacliSum(rawOutput)
+	if len(sum.SsacliSumData) != 2 {
+		t.Fatalf("expected 2 controllers, got %d", len(sum.SsacliSumData))
+	}
+
+	c := sum.SsacliSumData[0]
+	if c.ContTemp == nil || *c.ContTemp != 49 || c.CahceModuTemp == nil || *c.CahceModuTemp != 37 || c.BatteryTemp == nil || *c.BatteryTemp != 31 {
+		t.Errorf("unexpected temperatures for slot 0: %v, %v, %v", c.ContTemp, c.CahceModuTemp, c.BatteryTemp)
+	}
+
+	// A value that is not a number is reported instead of aborting
+	c = sum.SsacliSumData[1]
+	if c.ContTemp != nil || c.CahceModuTemp != nil || c.BatteryTemp != nil {
+		t.Errorf("slot 3 should report no temperatures, got %v, %v, %v", c.ContTemp, c.CahceModuTemp, c.BatteryTemp)
+	}
+	if sum.Err == nil {
+		t.Errorf("expected an error for the invalid controller temperature")
+	}
+}
+
+func TestParseSsacliSumSmartPath(t *testing.T) {
+	rawOutput := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   HPE SSD Smart Path: enable
+
+Smart Array P420i in Slot 1
+   Slot: 1
+   HP SSD Smart Path: disable
+
+Smart Array P410i in Slot 2
+   Slot: 2
+   Controller Status: OK
+`
+
+	data := ParseSsacliSum(rawOutput).SsacliSumData
+	if len(data) != 3 {
+		t.Fatalf("expected 3 controllers, got %d", len(data))
+	}
+
+	// Both the HPE and the older HP spelling are read, controllers that
+	// predate Smart Path report nothing
+	for i, want := range []string{"enable", "disable", ""} {
+		if data[i].SmartPath != want {
+			t.Errorf("slot %s: expected Smart Path %q, got %q", data[i].SlotID, want, data[i].SmartPath)
+		}
+	}
+}
+
+func TestParseSsacliSumCacheSizeUnits(t *testing.T) {
+	// hpacucli on a Gen8 server prints the cache sizes with a unit
+	rawOutput := `
+Smart Array P420i in Slot 0 (Embedded)
+   Bus Interface: PCI
+   Slot: 0
+   Serial Number: 5001438024D1A2B0
+   Cache Serial Number: PBKUC0BRH4P0ZQ
+   RAID 6 (ADG) Status: Enabled
+   Controller Status: OK
+   Hardware Revision: B
+   Firmware Version: 8.32
+   Total Cache Size: 1024 MB
+   Total Cache Memory Available: 912 MB
+   Cache Backup Power Source: Capacitors
+   Battery/Capacitor Count: 1
+   Battery/Capacitor Status: OK
+
+Smart Array P440ar in Slot 1
+   Slot: 1
+   Total Cache Size: 2.0
+   Total Cache Memory Available: 1.8
+   Cache Status: OK
+
+Smart Array P222 in Slot 2
+   Slot: 2
+   Total Cache Size: unknown
+`
+
+	sum := ParseSsacliSum(rawOutput)
+	if len(sum.SsacliSumData) != 3 {
+		t.Fatalf("expected 3 controllers, got %d", len(sum.SsacliSumData))
+	}
+
+	for i, want := range [][2]float64{{1, 912.0 / 1024}, {2, 1.8}} {
+		c := sum.SsacliSumData[i]
+		if c.TotalCacheSize == nil || *c.TotalCacheSize != want[0] || c.AvailCacheSize == nil || *c.AvailCacheSize != want[1] {
+			t.Errorf("slot %s: expected cache sizes %v GB, got %v, %v", c.SlotID, want, deref(c.TotalCacheSize), deref(c.AvailCacheSize))
+		}
+	}
+
+	// A size that cannot be read is reported instead of aborting
+	if c := sum.SsacliSumData[2]; c.TotalCacheSize != nil {
+		t.Errorf("slot 2 should report no cache size, got %v", *c.TotalCacheSize)
+	}
+	if sum.Err == nil {
+		t.Errorf("expected an error for the invalid cache size")
+	}
+}
@@ -0,0 +1,26 @@
+package parser
+
+import (
+	"strings"
+)
+
+// ParseSsacliVersion reads the CLI version from the output of
+// "ssacli version", which is printed as
+//
+//	SSACLI Version: 4.21.7.0 2020-09-28
+//	SOULAPI Version: 4.21.7.0 2020-09-28
+//
+// hpssacli and hpacucli label the line "HPSSACLI Version" and
+// "ACU CLI Version". It returns the empty string when no version is found.
+func ParseSsacliVersion(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok || !strings.HasSuffix(strings.ReplaceAll(key, " ", ""), "CLIVersion") {
+			continue
+		}
+		if fields := strings.Fields(val); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestParseSsacliVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"\n   SSACLI Version: 4.21.7.0 2020-09-28\n   SOULAPI Version: 4.21.7.0 2020-09-28\n", "4.21.7.0"},
+		{"\n   HPSSACLI Version: 2.40.13.0 2016-03-24\n   SOULAPI Version: 2.40.13.0 2016-03-24\n", "2.40.13.0"},
+		{"\n   ACU CLI Version: 9.40.12.0\n", "9.40.12.0"},
+		{"Error: \"version\" is not a valid command.\n", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseSsacliVersion(tt.output); got != tt.want {
+			t.Errorf("ParseSsacliVersion(%q): expected %q, got %q", tt.output, tt.want, got)
+		}
+	}
+}
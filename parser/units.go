@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Multiplier bases for ParseBytes. ssacli prints physical drive capacity
+// the way drive vendors do (decimal), while everything the controller
+// accounts for itself (logical drives, arrays, strips, cache) is printed
+// in binary units with the same "KB"/"MB"/"GB" suffixes.
+const (
+	DecimalBase = 1000
+	BinaryBase  = 1024
+)
+
+var sizeUnits = map[string]int{
+	"B":  0,
+	"KB": 1,
+	"MB": 2,
+	"GB": 3,
+	"TB": 4,
+	"PB": 5,
+}
+
+// ParseBytes converts a capacity such as "600 GB", "558.9 GB", "256 KB" or
+// "0 MB (0.0%)" into bytes using the given multiplier base.
+func ParseBytes(s string, base float64) (float64, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed size %q", s)
+	}
+
+	val, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed size %q: %w", s, err)
+	}
+
+	exp, ok := sizeUnits[strings.ToUpper(fields[1])]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit in %q", s)
+	}
+
+	for ; exp > 0; exp-- {
+		val *= base
+	}
+	return val, nil
+}
+
+// ParseGbps converts a SAS/SATA link rate such as "6.0Gbps" or "12.0 Gbps"
+// into Gbps. ssacli prints "Unknown" for PHYs without a link, which is
+// reported as an error.
+func ParseGbps(s string) (float64, error) {
+	v := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "Gbps"))
+	val, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed link rate %q: %w", s, err)
+	}
+	return val, nil
+}
@@ -0,0 +1,279 @@
+package smartarray
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/CloudOpsKit/smartctl_ssacli_exporter/parser"
+)
+
+// ParseController builds a Controller from the raw output of
+// "ssacli ctrl slot=N show detail", "ssacli ctrl slot=N pd all show detail"
+// and "ssacli ctrl slot=N ld all show detail". Any of them may be empty,
+// a Controller built without detail only carries its drives. When detail
+// lists several controllers the first one is used.
+//
+// Values that cannot be parsed are left unset and reported in the
+// returned error, the Controller is returned either way.
+func ParseController(detail, pdDetail, ldDetail string) (*Controller, error) {
+	var sum parser.SsacliSumData
+	parsed := parser.ParseSsacliSum(detail)
+	if len(parsed.SsacliSumData) > 0 {
+		sum = parsed.SsacliSumData[0]
+	}
+	return newController(sum, parser.ParseSsacliPhysDisk(pdDetail), parser.ParseSsacliLogDisk(ldDetail)), parsed.Err
+}
+
+func newController(sum parser.SsacliSumData, pds *parser.SsacliPhysDisk, lds *parser.SsacliLogDisk) *Controller {
+	c := &Controller{
+		Slot:            int(sum.Slot),
+		Model:           sum.Model,
+		SerialNumber:    sum.SerialNumber,
+		Status:          sum.ContStatus,
+		FirmwareVersion: sum.FirmVersion,
+	}
+	c.Cache = &Cache{Controller: c}
+	if sum.TotalCacheSize != nil {
+		c.Cache.TotalSize = Bytes(*sum.TotalCacheSize * (1 << 30))
+	}
+	if sum.AvailCacheSize != nil {
+		c.Cache.AvailableSize = Bytes(*sum.AvailCacheSize * (1 << 30))
+	}
+	c.Battery = &Battery{
+		Controller: c,
+		Status:     sum.BatteryStatus,
+	}
+
+	if sum.ContTemp != nil {
+		c.Temperature = Celsius(*sum.ContTemp)
+	}
+	if sum.CahceModuTemp != nil {
+		c.Cache.Temperature = Celsius(*sum.CahceModuTemp)
+	}
+	if sum.BatteryTemp != nil {
+		c.Battery.Temperature = Celsius(*sum.BatteryTemp)
+	}
+
+	if pds != nil {
+		for _, d := range pds.SsacliPhysDiskData {
+			c.addPhysicalDrive(newPhysicalDrive(d), d.Array)
+		}
+	}
+
+	if lds != nil {
+		for _, d := range lds.SsacliLogDiskData {
+			c.addLogicalDrive(newLogicalDrive(d), d.Array, d.PhysDisks)
+		}
+	}
+
+	return c
+}
+
+// AddArrayDetail fills in the array properties reported by
+// "ssacli ctrl slot=N array all show detail". Arrays without drives are
+// added to the controller.
+func (c *Controller) AddArrayDetail(detail string) {
+	for _, d := range parser.ParseSsacliArray(detail).SsacliArrayData {
+		a := c.array(d.ID)
+		a.Status = d.Status
+		a.Type = d.ArrayType
+		a.InterfaceType = d.InterfaceType
+		a.UsedSpace = Bytes(d.UsedSpaceBytes)
+		a.UnusedSpace = Bytes(d.UnusedSpaceBytes)
+	}
+}
+
+// PortType reports whether a controller port such as "1I" or "2E" is
+// "internal" or "external", empty when the port name carries no suffix.
+func PortType(port string) string {
+	switch {
+	case strings.HasSuffix(port, "I"):
+		return "internal"
+	case strings.HasSuffix(port, "E"):
+		return "external"
+	default:
+		return ""
+	}
+}
+
+// AddEnclosureDetail fills in the enclosures from the raw output of
+// "ssacli ctrl slot=N enclosure all show detail", adding the ones without
+// drives.
+func (c *Controller) AddEnclosureDetail(detail string) {
+	for _, d := range parser.ParseSsacliEnclosure(detail).SsacliEnclosureData {
+		box, err := strconv.Atoi(d.Box)
+		if err != nil {
+			continue
+		}
+		e := c.enclosure(d.Port, box)
+		e.Name = d.Name
+		e.Status = d.Status
+		e.Location = d.Location
+		e.SerialNumber = d.SerialNumber
+		e.Firmware = d.Firmware
+	}
+}
+
+// ParseDriveLocation splits a physical drive ID such as "1I:1:3" into the
+// controller port, box and bay it encodes.
+func ParseDriveLocation(id string) (port string, box, bay int, ok bool) {
+	parts := strings.Split(id, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", 0, 0, false
+	}
+	box, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, 0, false
+	}
+	bay, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return "", 0, 0, false
+	}
+	return parts[0], box, bay, true
+}
+
+func newPhysicalDrive(d parser.SsacliPhysDiskData) *PhysicalDrive {
+	pd := &PhysicalDrive{
+		ID:            d.ID,
+		Status:        d.Status,
+		DriveType:     d.DriveType,
+		Role:          d.Role(),
+		InterfaceType: d.IntType,
+		Model:         strings.Join(strings.Fields(d.Model), " "),
+		SerialNumber:  d.SN,
+		WWID:          d.WWID,
+		Firmware:      d.Firmware,
+	}
+	pd.Port, pd.Box, pd.Bay, _ = ParseDriveLocation(d.ID)
+
+	if d.CurTemp != nil {
+		pd.Temperature = Celsius(*d.CurTemp)
+	}
+	if d.MaxTemp != nil {
+		pd.MaxTemperature = Celsius(*d.MaxTemp)
+	}
+	if d.SizeBytes != nil {
+		pd.Size = Bytes(*d.SizeBytes)
+	}
+	if d.LogicalBlockSize != nil {
+		pd.LogicalBlockSize = Bytes(*d.LogicalBlockSize)
+	}
+	if d.PhysicalBlockSize != nil {
+		pd.PhysicalBlockSize = Bytes(*d.PhysicalBlockSize)
+	}
+	if d.RotationalSpeed != nil {
+		pd.RotationalSpeed = int(*d.RotationalSpeed)
+	}
+
+	if d.UsageRemaining != nil {
+		pd.Endurance = &Endurance{UsageRemaining: *d.UsageRemaining}
+		if d.PowerOnHours != nil {
+			pd.Endurance.PowerOnHours = int(*d.PowerOnHours)
+		}
+		if d.EstimatedLifeRemaining != nil {
+			pd.Endurance.EstimatedLifeRemainingDays = int(*d.EstimatedLifeRemaining)
+		}
+		pd.Endurance.WearoutTripped = d.SmartTripWearout != nil && *d.SmartTripWearout == 1
+	}
+
+	for _, r := range d.PHYTransferRates {
+		pd.LinkRates = append(pd.LinkRates, Gbps(r))
+	}
+	for _, r := range d.PHYMaximumLinkRates {
+		pd.MaxLinkRates = append(pd.MaxLinkRates, Gbps(r))
+	}
+
+	return pd
+}
+
+func newLogicalDrive(d parser.SsacliLogDiskData) *LogicalDrive {
+	ld := &LogicalDrive{
+		ID:               d.ID,
+		FaultTolerance:   d.FaultTolerance,
+		Status:           d.Status,
+		Caching:          d.Caching,
+		UniqueIdentifier: d.UID,
+		DiskName:         d.LName,
+		Label:            d.LID,
+	}
+
+	if d.SizeBytes != nil {
+		ld.Size = Bytes(*d.SizeBytes)
+	}
+	if d.StripSizeBytes != nil {
+		ld.StripSize = Bytes(*d.StripSizeBytes)
+	}
+	switch {
+	case d.FullStripeSizeBytes != nil:
+		ld.FullStripeSize = Bytes(*d.FullStripeSizeBytes)
+	case d.DataStripeSizeBytes != nil:
+		ld.FullStripeSize = Bytes(*d.DataStripeSizeBytes)
+	}
+	if d.ParityGroups != nil {
+		ld.ParityGroups = int(*d.ParityGroups)
+	}
+	if d.UnrecoverableMediaErrors != nil {
+		ld.UnrecoverableMediaErrors = int(*d.UnrecoverableMediaErrors)
+	}
+
+	return ld
+}
+
+func (c *Controller) array(id string) *Array {
+	for _, a := range c.Arrays {
+		if a.ID == id {
+			return a
+		}
+	}
+	a := &Array{Controller: c, ID: id}
+	c.Arrays = append(c.Arrays, a)
+	return a
+}
+
+func (c *Controller) enclosure(port string, box int) *Enclosure {
+	for _, e := range c.Enclosures {
+		if e.Port == port && e.Box == box {
+			return e
+		}
+	}
+	e := &Enclosure{Controller: c, Port: port, Box: box}
+	c.Enclosures = append(c.Enclosures, e)
+	return e
+}
+
+func (c *Controller) addPhysicalDrive(pd *PhysicalDrive, arrayID string) {
+	// A spare shared by several arrays is listed once per array
+	if known := c.PhysicalDrive(pd.ID); known != nil {
+		if arrayID != "" {
+			a := c.array(arrayID)
+			a.PhysicalDrives = append(a.PhysicalDrives, known)
+		}
+		return
+	}
+
+	pd.Controller = c
+
+	if arrayID == "" {
+		c.UnassignedDrives = append(c.UnassignedDrives, pd)
+	} else {
+		pd.Array = c.array(arrayID)
+		pd.Array.PhysicalDrives = append(pd.Array.PhysicalDrives, pd)
+	}
+
+	if pd.Port != "" {
+		pd.Enclosure = c.enclosure(pd.Port, pd.Box)
+		pd.Enclosure.PhysicalDrives = append(pd.Enclosure.PhysicalDrives, pd)
+	}
+}
+
+func (c *Controller) addLogicalDrive(ld *LogicalDrive, arrayID string, members []string) {
+	ld.Controller = c
+	ld.Array = c.array(arrayID)
+	ld.Array.LogicalDrives = append(ld.Array.LogicalDrives, ld)
+
+	for _, id := range members {
+		if pd := c.PhysicalDrive(id); pd != nil {
+			ld.PhysicalDrives = append(ld.PhysicalDrives, pd)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package smartarray is a typed model of HPE Smart Array controllers as
+// reported by ssacli.
+//
+// A Controller owns its Cache and Battery, the Arrays configured on it,
+// the drives not assigned to any array and the Enclosures its drives sit
+// in. Arrays own their LogicalDrives and PhysicalDrives, and every entity
+// points back to its parents, so callers can walk the topology in either
+// direction without re-parsing ssacli output. Capacities are converted to
+// Bytes, temperatures to Celsius and link rates to Gbps.
+//
+// Values are built straight from ssacli text with ParseController, which
+// AddArrayDetail and AddEnclosureDetail complete with the array and
+// enclosure listings.
+//
+// # Compatibility
+//
+// This package is the stable API of the module and follows semantic
+// versioning. Within a major version:
+//
+//   - exported identifiers are not removed or renamed, and the meaning and
+//     unit of existing fields does not change;
+//   - new types, functions, methods and struct fields may be added, so use
+//     keyed struct literals;
+//   - status and other free-text fields carry the string printed by ssacli
+//     unchanged, and new firmware may print values not seen before;
+//   - fields referring to entities owned elsewhere in the tree (parents,
+//     enclosure and logical drive membership) are excluded from JSON
+//     encoding, so a Controller encodes as a tree without cycles.
+//
+// The parser, collector and exporter packages are implementation details of
+// the exporter and carry no such promise.
+package smartarray
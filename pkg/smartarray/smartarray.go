@@ -0,0 +1,215 @@
+package smartarray
+
+// Bytes is a capacity in bytes.
+type Bytes uint64
+
+// Celsius is a temperature in degrees Celsius.
+type Celsius float64
+
+// Gbps is a link rate in gigabits per second.
+type Gbps float64
+
+// Controller is a Smart Array controller.
+type Controller struct {
+	Slot            int
+	Model           string
+	SerialNumber    string
+	Status          string
+	FirmwareVersion string
+	Temperature     Celsius
+
+	Cache   *Cache
+	Battery *Battery
+
+	Arrays           []*Array
+	UnassignedDrives []*PhysicalDrive
+	Enclosures       []*Enclosure
+}
+
+// Cache is the flash-backed write cache module of a controller.
+type Cache struct {
+	Controller *Controller `json:"-"`
+
+	TotalSize     Bytes
+	AvailableSize Bytes
+	Temperature   Celsius
+}
+
+// Battery is the battery or capacitor pack backing the controller cache.
+type Battery struct {
+	Controller *Controller `json:"-"`
+
+	Status      string
+	Temperature Celsius
+}
+
+// Array is a group of physical drives that logical drives are carved from.
+type Array struct {
+	Controller *Controller `json:"-"`
+
+	ID            string
+	Status        string
+	Type          string
+	InterfaceType string
+	UsedSpace     Bytes
+	UnusedSpace   Bytes
+
+	LogicalDrives []*LogicalDrive
+	// PhysicalDrives includes spares shared with other arrays.
+	PhysicalDrives []*PhysicalDrive
+}
+
+// LogicalDrive is a RAID volume exposed to the operating system.
+type LogicalDrive struct {
+	Controller *Controller `json:"-"`
+	Array      *Array      `json:"-"`
+
+	ID               string
+	Size             Bytes
+	FaultTolerance   string
+	Status           string
+	Caching          string
+	UniqueIdentifier string
+	DiskName         string
+	Label            string
+
+	StripSize      Bytes
+	FullStripeSize Bytes
+	// ParityGroups is 0 unless the drive is RAID 50 or RAID 60.
+	ParityGroups int
+	// UnrecoverableMediaErrors counts blocks the controller could not
+	// recover, i.e. data that has been lost.
+	UnrecoverableMediaErrors int
+
+	PhysicalDrives []*PhysicalDrive `json:"-"`
+}
+
+// PhysicalDrive is a drive attached to the controller. Array is nil for
+// unassigned drives and the first array listing it for spares shared by
+// several arrays.
+type PhysicalDrive struct {
+	Controller *Controller `json:"-"`
+	Array      *Array      `json:"-"`
+	Enclosure  *Enclosure  `json:"-"`
+
+	ID        string
+	Port      string
+	Box       int
+	Bay       int
+	Status    string
+	DriveType string
+	// Role is one of "data", "spare" or "unassigned".
+	Role          string
+	InterfaceType string
+	Model         string
+	SerialNumber  string
+	WWID          string
+	Firmware      string
+
+	Size              Bytes
+	LogicalBlockSize  Bytes
+	PhysicalBlockSize Bytes
+	// RotationalSpeed is in rpm, 0 for solid state drives.
+	RotationalSpeed int
+	Temperature     Celsius
+	MaxTemperature  Celsius
+
+	// LinkRates holds the negotiated rate of every PHY, zero for PHYs
+	// without a link.
+	LinkRates []Gbps
+	// MaxLinkRates holds the highest rate every PHY supports, when
+	// reported by ssacli.
+	MaxLinkRates []Gbps
+
+	// Endurance is nil for drives that do not report wear, i.e. hard
+	// disks.
+	Endurance *Endurance
+}
+
+// Endurance is the wear of a solid state drive as reported by the
+// controller.
+type Endurance struct {
+	// UsageRemaining is the ratio of rated endurance left, 0 to 1.
+	UsageRemaining             float64
+	PowerOnHours               int
+	EstimatedLifeRemainingDays int
+	// WearoutTripped is set once the drive crossed its SMART wearout
+	// threshold.
+	WearoutTripped bool
+}
+
+// Enclosure is a drive cage or external enclosure identified by the
+// controller port and box number it is connected through.
+type Enclosure struct {
+	Controller *Controller `json:"-"`
+
+	Port string
+	Box  int
+
+	// The fields below are only set after AddEnclosureDetail.
+	Name         string
+	Status       string
+	Location     string
+	SerialNumber string
+	Firmware     string
+
+	PhysicalDrives []*PhysicalDrive `json:"-"`
+}
+
+// LogicalDrive returns the logical drive with the given ID, or nil.
+func (c *Controller) LogicalDrive(id string) *LogicalDrive {
+	for _, a := range c.Arrays {
+		for _, ld := range a.LogicalDrives {
+			if ld.ID == id {
+				return ld
+			}
+		}
+	}
+	return nil
+}
+
+// PhysicalDrive returns the physical drive with the given ID, or nil.
+func (c *Controller) PhysicalDrive(id string) *PhysicalDrive {
+	for _, pd := range c.PhysicalDrives() {
+		if pd.ID == id {
+			return pd
+		}
+	}
+	return nil
+}
+
+// PhysicalDrives returns every physical drive of the controller once, array
+// members first.
+func (c *Controller) PhysicalDrives() []*PhysicalDrive {
+	var (
+		pds  []*PhysicalDrive
+		seen = make(map[*PhysicalDrive]bool)
+	)
+	for _, a := range c.Arrays {
+		for _, pd := range a.PhysicalDrives {
+			if !seen[pd] {
+				seen[pd] = true
+				pds = append(pds, pd)
+			}
+		}
+	}
+	return append(pds, c.UnassignedDrives...)
+}
+
+// LogicalDrives returns the logical drives the physical drive is a member
+// of.
+func (pd *PhysicalDrive) LogicalDrives() []*LogicalDrive {
+	if pd.Array == nil {
+		return nil
+	}
+	var lds []*LogicalDrive
+	for _, ld := range pd.Array.LogicalDrives {
+		for _, member := range ld.PhysicalDrives {
+			if member == pd {
+				lds = append(lds, ld)
+				break
+			}
+		}
+	}
+	return lds
+}
@@ -0,0 +1,132 @@
+package smartarray
+
+import (
+	"testing"
+)
+
+func TestParseController(t *testing.T) {
+	detail := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: 0
+   Serial Number: PDNLH0BRH7V1OP
+   Controller Status: OK
+   Firmware Version: 6.60-0
+   Total Cache Size: 2.0
+   Total Cache Memory Available: 1.8
+   Battery/Capacitor Status: OK
+   Controller Temperature (C): 49
+`
+	pdDetail := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      physicaldrive 1I:1:1
+         Port: 1I
+         Box: 1
+         Bay: 1
+         Status: OK
+         Drive Type: Data Drive
+         Interface Type: SAS
+         Size: 600 GB
+         Model: HP      EG0600FBVFP
+         Current Temperature (C): 31
+         PHY Transfer Rate: 6.0Gbps, Unknown
+
+      physicaldrive 1I:1:2
+         Status: OK
+         Drive Type: Data Drive
+         Size: 600 GB
+
+   Unassigned
+
+      physicaldrive 1I:1:3
+         Status: OK
+         Drive Type: Unassigned Drive
+         Size: 600 GB
+`
+	ldDetail := `
+Smart Array P440ar in Slot 0 (Embedded)
+
+   Array A
+
+      Logical Drive: 1
+         Size: 558.9 GB
+         Fault Tolerance: 1
+         Status: OK
+         Mirror Group 1:
+            physicaldrive 1I:1:1 (port 1I:box 1:bay 1, SAS HDD, 600 GB, OK)
+         Mirror Group 2:
+            physicaldrive 1I:1:2 (port 1I:box 1:bay 2, SAS HDD, 600 GB, OK)
+`
+
+	c, err := ParseController(detail, pdDetail, ldDetail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Slot != 0 || c.Model != "Smart Array P440ar" || c.Cache.TotalSize != 2<<30 {
+		t.Errorf("unexpected controller: %+v", c)
+	}
+	if len(c.Arrays) != 1 || len(c.Arrays[0].PhysicalDrives) != 2 || len(c.UnassignedDrives) != 1 {
+		t.Fatalf("unexpected array layout: %d arrays, %d unassigned", len(c.Arrays), len(c.UnassignedDrives))
+	}
+
+	pd := c.PhysicalDrive("1I:1:1")
+	if pd == nil {
+		t.Fatal("physical drive 1I:1:1 not found")
+	}
+	if pd.Size != 600e9 || pd.Model != "HP EG0600FBVFP" || pd.Port != "1I" || pd.Box != 1 || pd.Bay != 1 {
+		t.Errorf("unexpected physical drive: %+v", pd)
+	}
+	if len(pd.LinkRates) != 2 || pd.LinkRates[0] != 6 || pd.LinkRates[1] != 0 {
+		t.Errorf("unexpected link rates: %v", pd.LinkRates)
+	}
+
+	lds := pd.LogicalDrives()
+	if len(lds) != 1 || lds[0].ID != "1" || len(lds[0].PhysicalDrives) != 2 {
+		t.Fatalf("unexpected logical drive membership: %+v", lds)
+	}
+	if lds[0].Array != pd.Array || pd.Enclosure == nil || len(pd.Enclosure.PhysicalDrives) != 3 {
+		t.Errorf("relationships not linked")
+	}
+}
+
+func TestParseControllerInvalidValues(t *testing.T) {
+	detail := `
+Smart Array P440ar in Slot 0 (Embedded)
+   Slot: X
+   Serial Number: PDNLH0BRH7V1OP
+   Controller Temperature (C): N/A
+`
+
+	c, err := ParseController(detail, "", "")
+	if err == nil {
+		t.Fatal("expected an error for the invalid slot and temperature")
+	}
+	if c == nil || c.SerialNumber != "PDNLH0BRH7V1OP" || c.Temperature != 0 {
+		t.Errorf("unexpected controller: %+v", c)
+	}
+}
+
+func TestParseDriveLocation(t *testing.T) {
+	for _, tc := range []struct {
+		id       string
+		port     string
+		portType string
+		box, bay int
+		ok       bool
+	}{
+		{"1I:1:3", "1I", "internal", 1, 3, true},
+		{"2E:1:12", "2E", "external", 1, 12, true},
+		{"CN0:1", "", "", 0, 0, false},
+	} {
+		port, box, bay, ok := ParseDriveLocation(tc.id)
+		if port != tc.port || box != tc.box || bay != tc.bay || ok != tc.ok {
+			t.Errorf("%s: got %q %d %d %v", tc.id, port, box, bay, ok)
+		}
+		if got := PortType(port); got != tc.portType {
+			t.Errorf("%s: expected port type %q, got %q", tc.id, tc.portType, got)
+		}
+	}
+}